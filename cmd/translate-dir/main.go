@@ -0,0 +1,48 @@
+// Command translate-dir 使用 LLM 翻译目录下的 .txt 和 .md 文件，译文写在原文旁边的 name.<lang>.ext 中。
+//
+// 用法：
+//
+//	translate-dir -root docs -to Chinese [-glob "*.md"]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/costa92/langchaingo-demo/pkg/provider"
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+func main() {
+	root := flag.String("root", "", "directory to translate (required)")
+	glob := flag.String("glob", "", `file name pattern, e.g. "*.md" (default: all .txt and .md files)`)
+	to := flag.String("to", "Chinese", "target language")
+	model := flag.String("model", "Qwen/Qwen3-30B-A3B", "model name")
+	flag.Parse()
+
+	if *root == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	llm, err := provider.NewLLM(provider.LLMConfig{
+		Provider:  provider.ProviderOpenAI,
+		BaseURL:   os.Getenv(provider.EnvAPIURL),
+		Model:     *model,
+		APIKeyEnv: provider.EnvAPIKey,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize LLM: %v", err)
+	}
+
+	written, err := translator.TranslateDir(context.Background(), llm, *root, *glob, *to)
+	for _, path := range written {
+		log.Printf("Written %s", path)
+	}
+	if err != nil {
+		log.Fatalf("Failed to translate %s: %v", *root, err)
+	}
+	log.Printf("Translated %d files", len(written))
+}
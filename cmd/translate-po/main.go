@@ -0,0 +1,62 @@
+// Command translate-po 使用 LLM 填充 gettext .po 文件中未翻译的条目。
+//
+// 用法：
+//
+//	translate-po -in messages.po -out messages.zh.po -from English -to Chinese [-force]
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/costa92/langchaingo-demo/pkg/provider"
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+func main() {
+	in := flag.String("in", "", "input .po file (required)")
+	out := flag.String("out", "", "output .po file (default: overwrite input)")
+	from := flag.String("from", "English", "source language")
+	to := flag.String("to", "Chinese", "target language")
+	model := flag.String("model", "Qwen/Qwen3-30B-A3B", "model name")
+	force := flag.Bool("force", false, "re-translate entries that already have a msgstr")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *out == "" {
+		*out = *in
+	}
+
+	llm, err := provider.NewLLM(provider.LLMConfig{
+		Provider:  provider.ProviderOpenAI,
+		BaseURL:   os.Getenv(provider.EnvAPIURL),
+		Model:     *model,
+		APIKeyEnv: provider.EnvAPIKey,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize LLM: %v", err)
+	}
+
+	src, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *in, err)
+	}
+	defer src.Close()
+
+	// 先写入内存，成功后再落盘，避免覆盖输入文件时丢失内容
+	var buf bytes.Buffer
+	n, err := translator.TranslatePO(context.Background(), llm, src, &buf, *from, *to, *force)
+	if err != nil {
+		log.Fatalf("Failed to translate %s: %v", *in, err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+	log.Printf("Translated %d entries, written to %s", n, *out)
+}
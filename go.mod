@@ -2,7 +2,12 @@ module github.com/costa92/langchaingo-demo
 
 go 1.24.1
 
-require github.com/tmc/langchaingo v0.1.13
+require (
+	github.com/tmc/langchaingo v0.1.13
+	golang.org/x/net v0.38.0
+	golang.org/x/sync v0.12.0
+	golang.org/x/text v0.23.0
+)
 
 require (
 	github.com/Masterminds/goutils v1.1.1 // indirect
@@ -28,8 +33,8 @@ require (
 	github.com/spf13/cast v1.3.1 // indirect
 	github.com/yargevad/filepathx v1.0.0 // indirect
 	go.starlark.net v0.0.0-20230302034142-4b1e35fe2254 // indirect
-	golang.org/x/crypto v0.29.0 // indirect
+	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/exp v0.0.0-20230713183714-613f0c0eb8a1 // indirect
-	golang.org/x/sys v0.27.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -7,51 +7,85 @@ import (
 	"time"
 
 	"github.com/tmc/langchaingo/agents"
-	"github.com/tmc/langchaingo/chains"
-	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/tools"
 
 	"github.com/costa92/langchaingo-demo/pkg/translator"
 )
 
 // TranslateWithAgent 使用完整的 agent 执行器进行翻译
-func TranslateWithAgent(ctx context.Context, llm *openai.LLM, text string, inputLanguage string, outputLanguage string) (string, error) {
+func TranslateWithAgent(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
 	// 添加超时控制，避免长时间阻塞
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	// 输入验证
 	if text == "" {
-		return "", fmt.Errorf("empty text")
+		return "", translator.ErrEmptyText
 	}
 	if inputLanguage == "" {
-		return "", fmt.Errorf("empty input language")
+		return "", translator.ErrEmptyInputLanguage
 	}
 	if outputLanguage == "" {
-		return "", fmt.Errorf("empty output language")
+		return "", translator.ErrEmptyOutputLanguage
 	}
 	if llm == nil {
 		return "", fmt.Errorf("LLM client is nil")
 	}
 
-	log.Printf("Starting agent-based translation: '%s' from %s to %s", text, inputLanguage, outputLanguage)
+	return runAgent(ctx, llm, text, inputLanguage, outputLanguage, newOptions(opts))
+}
+
+// runAgent 构建 one-shot agent 并执行一次翻译，调用方负责输入验证和超时控制
+func runAgent(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	return runExecutor(ctx, newAgentExecutor(llm, o, defaultMaxIterations), text, inputLanguage, outputLanguage, o)
+}
 
-	// 优化工具初始化，使用更高效的配置
-	translatorTool := translator.NewTranslator(llm)
-	calculatorTool := tools.Calculator{}
-	agentTools := []tools.Tool{translatorTool, &calculatorTool}
+// agent 执行器最多的推理步数
+const (
+	defaultMaxIterations   = 2 // TranslateWithAgent 和批量翻译
+	optimizedMaxIterations = 3 // TranslateWithAgentOptimized
+)
+
+// newAgentExecutor 创建使用 agentTools 工具列表、最多执行 maxIterations 步的 one-shot agent 执行器，
+// 执行器的回调处理器是统计翻译工具调用的 toolRecorder。定义为变量以便测试统计构建次数
+var newAgentExecutor = func(llm llms.Model, o *options, maxIterations int) *agents.Executor {
+	recorder := newToolRecorder(o.callbacks)
+	agentOpts := append([]agents.Option{agents.WithMaxIterations(maxIterations), agents.WithCallbacksHandler(recorder)}, o.agentOptions()...)
+	agent := agents.NewOneShotAgent(llm, agentTools(llm, o), agentOpts...)
+	return agents.NewExecutor(agent, agents.WithCallbacksHandler(recorder))
+}
+
+// agentTools 返回 agent 可用的工具：翻译工具，以及通过 WithCalculator 开启的计算器
+func agentTools(llm llms.Model, o *options) []tools.Tool {
+	toolList := []tools.Tool{translator.NewTranslator(llm)}
+	if o.calculator {
+		toolList = append(toolList, &tools.Calculator{})
+	}
+	return toolList
+}
+
+// runExecutor 用已构建的执行器翻译一段文本，执行器可以在多次调用之间复用
+func runExecutor(ctx context.Context, executor *agents.Executor, text string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	log.Printf("Starting agent-based translation: '%s' from %s to %s", text, inputLanguage, outputLanguage)
 
 	// 构建简化的输入提示
 	inputText := fmt.Sprintf("Translate '%s' from %s to %s.", text, inputLanguage, outputLanguage)
 
-	agent := agents.NewOneShotAgent(llm, agentTools, agents.WithMaxIterations(2))
-
-	executor := agents.NewExecutor(agent)
-	// 执行 agent
-	result, err := chains.Run(ctx, executor, inputText)
+	// 执行 agent，前后对比翻译工具的调用次数，执行器复用时也只统计本次运行
+	recorder, _ := executor.CallbacksHandler.(*toolRecorder)
+	var before int64
+	if recorder != nil {
+		before = recorder.calls()
+	}
+	result, err := runChain(ctx, executor, inputText)
 	if err != nil {
 		log.Printf("Translation failed: %v", err)
-		return "", fmt.Errorf("translation failed: %w", err)
+		return "", fmt.Errorf("translation failed: %w", translator.ClassifyError(err))
+	}
+	if o.requireTranslateTool && recorder != nil && recorder.calls() == before {
+		log.Printf("Agent answered without calling %s: %s", translateToolName, result)
+		return "", ErrTranslateToolNotUsed
 	}
 	log.Printf("Translation successful: %s", result)
 	return result, nil
@@ -6,61 +6,37 @@ import (
 	"log"
 	"time"
 
-	"github.com/tmc/langchaingo/agents"
-	"github.com/tmc/langchaingo/chains"
-	"github.com/tmc/langchaingo/llms/openai"
-	"github.com/tmc/langchaingo/tools"
+	"github.com/tmc/langchaingo/llms"
 
 	"github.com/costa92/langchaingo-demo/pkg/translator"
 )
 
+// retryBackoff 是 TranslateWithAgentOptimized 重试退避的基础时间，测试中可以调整
+var retryBackoff = 100 * time.Millisecond
+
 // TranslateWithAgent 使用完整的 agent 执行器进行翻译（性能优化版本）
-func TranslateWithAgentOptimized(ctx context.Context, llm *openai.LLM, text string, inputLanguage string, outputLanguage string) (string, error) {
+func TranslateWithAgentOptimized(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
 	// 添加超时控制
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// 输入验证
 	if text == "" {
-		return "", fmt.Errorf("empty text")
+		return "", translator.ErrEmptyText
 	}
 	if inputLanguage == "" {
-		return "", fmt.Errorf("empty input language")
+		return "", translator.ErrEmptyInputLanguage
 	}
 	if outputLanguage == "" {
-		return "", fmt.Errorf("empty output language")
+		return "", translator.ErrEmptyOutputLanguage
 	}
 	if llm == nil {
 		return "", fmt.Errorf("LLM client is nil")
 	}
 
-	log.Printf("Starting optimized agent-based translation: '%s' from %s to %s", text, inputLanguage, outputLanguage)
-
-	// 创建翻译工具（只创建一次）
-	trans := translator.NewTranslator(llm)
-	if trans == nil {
-		return "", fmt.Errorf("failed to create translator")
-	}
-
-	log.Printf("Created translator tool: name=%s", trans.Name())
-	log.Printf("Translator description: %s", trans.Description())
-
-	// 创建工具列表
-	toolList := []tools.Tool{trans}
-
-	// 构建简化的输入提示
-	inputText := fmt.Sprintf("Translate '%s' from %s to %s.", text, inputLanguage, outputLanguage)
-
-	// 初始化 agent 执行器（只初始化一次）
-	executor, err := agents.Initialize(
-		llm,
-		toolList,
-		agents.ZeroShotReactDescription,
-		agents.WithMaxIterations(3),
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to initialize agent: %w", err)
-	}
+	// 与 TranslateWithAgent 使用同样的执行器（只初始化一次），回调处理器和 WithRequireTranslateTool 同样生效
+	o := newOptions(opts)
+	executor := newAgentExecutor(llm, o, optimizedMaxIterations)
 
 	// 添加优化的重试机制
 	maxRetries := 2
@@ -73,21 +49,31 @@ func TranslateWithAgentOptimized(ctx context.Context, llm *openai.LLM, text stri
 		}
 
 		if retry > 0 {
+			// 与翻译工具内部的重试共享 context 中的重试预算
+			if !translator.SpendRetry(ctx) {
+				return "", fmt.Errorf("translation failed, retry budget exhausted: %w", lastError)
+			}
 			log.Printf("Retrying translation (attempt %d/%d)...", retry+1, maxRetries)
-			// 使用指数退避策略
-			backoff := time.Duration(retry*retry) * 100 * time.Millisecond
-			time.Sleep(backoff)
+			// 使用指数退避策略，等待期间 context 取消时立即返回
+			backoff := time.Duration(retry*retry) * retryBackoff
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(backoff):
+			}
 		}
 
-		// 执行 agent
-		result, err := chains.Run(ctx, executor, inputText)
+		// 执行 agent；runExecutor 已对错误分类，agent 没有调用翻译工具时返回 ErrTranslateToolNotUsed
+		result, err := runExecutor(ctx, executor, text, inputLanguage, outputLanguage, o)
 		if err != nil {
 			log.Printf("Translation attempt %d failed: %v", retry+1, err)
 			lastError = err
+			// 认证失败、参数错误等重试也无法成功，直接返回
+			if !translator.IsRetryable(lastError) {
+				return "", lastError
+			}
 			continue
 		}
-
-		log.Printf("Translation successful: %s", result)
 		return result, nil
 	}
 
@@ -2,15 +2,53 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
 )
 
+// fakeLLM 是用于测试的 llms.Model 实现，按 prompt 内容返回预设的回复
+type fakeLLM struct {
+	respond func(prompt string) (string, error)
+}
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var parts []string
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			if tc, ok := p.(llms.TextContent); ok {
+				parts = append(parts, tc.Text)
+			}
+		}
+	}
+	out, err := f.respond(strings.Join(parts, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: out}}}, nil
+}
+
+func (f *fakeLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
 // setupLLM 设置 LLM 客户端
 func setupLLM(t *testing.T, useTestToken bool) *openai.LLM {
 	var token string
@@ -156,3 +194,401 @@ func TestTranslateWithAgent_ErrorHandling(t *testing.T) {
 	}
 	fmt.Println(result)
 }
+
+func TestTranslateWithAgent_TypedErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		text       string
+		inputLang  string
+		outputLang string
+		wantErr    error
+	}{
+		{name: "Empty Text", text: "", inputLang: "English", outputLang: "Chinese", wantErr: translator.ErrEmptyText},
+		{name: "Empty Input Language", text: "Hello", inputLang: "", outputLang: "Chinese", wantErr: translator.ErrEmptyInputLanguage},
+		{name: "Empty Output Language", text: "Hello", inputLang: "English", outputLang: "", wantErr: translator.ErrEmptyOutputLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TranslateWithAgent(ctx, nil, tt.text, tt.inputLang, tt.outputLang); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TranslateWithAgent() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := TranslateWithAgentOptimized(ctx, nil, tt.text, tt.inputLang, tt.outputLang); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TranslateWithAgentOptimized() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, _, err := TranslateWithAgentFallback(ctx, nil, tt.text, tt.inputLang, tt.outputLang); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TranslateWithAgentFallback() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTranslateWithAgentFallback(t *testing.T) {
+	ctx := context.Background()
+	directPrompt := `Translate "Hello" from English to Chinese`
+
+	tests := []struct {
+		name       string
+		agentReply string
+		want       string
+		wantPath   TranslationPath
+	}{
+		{
+			name:       "Agent Succeeds",
+			agentReply: "Thought: I know the answer.\nFinal Answer: 你好（agent）",
+			want:       "你好（agent）",
+			wantPath:   PathAgent,
+		},
+		{
+			name:       "Agent Output Unparseable",
+			agentReply: "I am not sure what to do.",
+			want:       "你好",
+			wantPath:   PathDirect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &fakeLLM{respond: func(prompt string) (string, error) {
+				if strings.Contains(prompt, directPrompt) {
+					return "你好", nil
+				}
+				return tt.agentReply, nil
+			}}
+
+			got, path, err := TranslateWithAgentFallback(ctx, llm, "Hello", "English", "Chinese")
+			if err != nil {
+				t.Fatalf("TranslateWithAgentFallback() error = %v", err)
+			}
+			if got != tt.want || path != tt.wantPath {
+				t.Errorf("TranslateWithAgentFallback() = %q via %s, want %q via %s", got, path, tt.want, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestTranslateWithAgentFallback_AgentError(t *testing.T) {
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, `Translate "Hello" from English to Chinese`) {
+			return "你好", nil
+		}
+		return "", errors.New("agent provider unavailable")
+	}}
+
+	got, path, err := TranslateWithAgentFallback(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateWithAgentFallback() error = %v", err)
+	}
+	if got != "你好" || path != PathDirect {
+		t.Errorf("TranslateWithAgentFallback() = %q via %s, want %q via %s", got, path, "你好", PathDirect)
+	}
+}
+
+func TestTranslateBatchWithAgent(t *testing.T) {
+	ctx := context.Background()
+
+	// 统计执行器的构建次数
+	built := 0
+	orig := newAgentExecutor
+	newAgentExecutor = func(llm llms.Model, o *options, maxIterations int) *agents.Executor {
+		built++
+		return orig(llm, o, maxIterations)
+	}
+	t.Cleanup(func() { newAgentExecutor = orig })
+
+	answers := map[string]string{"Hello": "你好", "Thanks": "谢谢", "Bye": "再见"}
+	calls := 0
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		calls++
+		for src, dst := range answers {
+			if strings.Contains(prompt, fmt.Sprintf("Translate '%s'", src)) {
+				return "Thought: I know the answer.\nFinal Answer: " + dst, nil
+			}
+		}
+		return "", errors.New("unexpected prompt")
+	}}
+
+	texts := []string{"Hello", "Thanks", "Bye", "Hello"}
+	got, err := TranslateBatchWithAgent(ctx, llm, texts, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchWithAgent() error = %v", err)
+	}
+
+	want := []string{"你好", "谢谢", "再见", "你好"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if built != 1 {
+		t.Errorf("executor built %d times, want 1", built)
+	}
+	if calls != 3 {
+		t.Errorf("LLM called %d times, want 3 (duplicate text reused)", calls)
+	}
+}
+
+func TestTranslateBatchWithAgent_Errors(t *testing.T) {
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "", errors.New("agent provider unavailable")
+	}}
+
+	if _, err := TranslateBatchWithAgent(ctx, llm, []string{"Hello", ""}, "English", "Chinese"); !errors.Is(err, translator.ErrEmptyText) {
+		t.Errorf("empty text error = %v, want ErrEmptyText", err)
+	}
+
+	_, err := TranslateBatchWithAgent(ctx, llm, []string{"Hello"}, "English", "Chinese")
+	if err == nil || !strings.Contains(err.Error(), "index 0") {
+		t.Errorf("TranslateBatchWithAgent() error = %v, want error mentioning index 0", err)
+	}
+}
+
+func TestTranslateWithAgentFallback_PromptPrefix(t *testing.T) {
+	ctx := context.Background()
+	prefix := "Only use the translate_text tool. Never use the calculator."
+	suffix := "The final answer must contain the translation only."
+
+	var agentPrompt string
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		agentPrompt = prompt
+		return "Thought: I know the answer.\nFinal Answer: 你好", nil
+	}}
+
+	_, path, err := TranslateWithAgentFallback(ctx, llm, "Hello", "English", "Chinese",
+		WithPromptPrefix(prefix), WithPromptSuffix(suffix))
+	if err != nil {
+		t.Fatalf("TranslateWithAgentFallback() error = %v", err)
+	}
+	if path != PathAgent {
+		t.Fatalf("path = %s, want %s", path, PathAgent)
+	}
+
+	if !strings.HasPrefix(agentPrompt, prefix) {
+		t.Errorf("agent prompt does not start with the configured prefix:\n%s", agentPrompt)
+	}
+	if !strings.Contains(agentPrompt, suffix+"\n\nBegin!") {
+		t.Errorf("agent prompt does not contain the configured suffix before the question:\n%s", agentPrompt)
+	}
+	// 工具说明和问题仍然保留
+	for _, want := range []string{"calculator", "Question: Translate 'Hello' from English to Chinese."} {
+		if !strings.Contains(agentPrompt, want) {
+			t.Errorf("agent prompt missing %q:\n%s", want, agentPrompt)
+		}
+	}
+}
+
+// actionSpy 记录 agent 执行的工具调用
+type actionSpy struct {
+	callbacks.SimpleHandler
+	tools []string
+}
+
+func (s *actionSpy) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	s.tools = append(s.tools, action.Tool)
+}
+
+func TestTranslateWithAgentFallback_UsesTranslateTool(t *testing.T) {
+	ctx := context.Background()
+
+	// agent 第一步调用翻译工具，拿到观察结果后给出最终答案
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if !strings.Contains(prompt, "Question: Translate 'Hello'") {
+			return "你好", nil // 翻译工具内部的模型调用
+		}
+		if strings.Contains(prompt, "Observation: 你好") {
+			return "Thought: I now know the final answer.\nFinal Answer: 你好", nil
+		}
+		return "Thought: I should use the translation tool.\nAction: translate_text\nAction Input: Hello", nil
+	}}
+
+	spy := &actionSpy{}
+	got, path, err := TranslateWithAgentFallback(ctx, llm, "Hello", "English", "Chinese",
+		WithCallbacksHandler(spy), WithRequireTranslateTool())
+	if err != nil {
+		t.Fatalf("TranslateWithAgentFallback() error = %v", err)
+	}
+	if got != "你好" || path != PathAgent {
+		t.Errorf("TranslateWithAgentFallback() = %q via %s, want 你好 via %s", got, path, PathAgent)
+	}
+	if len(spy.tools) == 0 || spy.tools[0] != "translate_text" {
+		t.Errorf("recorded tool calls = %v, want translate_text at least once", spy.tools)
+	}
+}
+
+func TestTranslateBatchWithAgent_RequireTranslateTool(t *testing.T) {
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Thought: I know the answer.\nFinal Answer: 你好", nil
+	}}
+
+	// 默认允许 agent 直接作答
+	if _, err := TranslateBatchWithAgent(ctx, llm, []string{"Hello"}, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateBatchWithAgent() error = %v", err)
+	}
+
+	_, err := TranslateBatchWithAgent(ctx, llm, []string{"Hello"}, "English", "Chinese", WithRequireTranslateTool())
+	if !errors.Is(err, ErrTranslateToolNotUsed) {
+		t.Errorf("TranslateBatchWithAgent() error = %v, want ErrTranslateToolNotUsed", err)
+	}
+}
+
+func TestTranslateWithAgentOptimized_CancelDuringBackoff(t *testing.T) {
+	// 退避时间远大于测试允许的返回时间
+	orig := retryBackoff
+	retryBackoff = 10 * time.Second
+	t.Cleanup(func() { retryBackoff = orig })
+
+	// 一直返回可重试的 429，让第一次尝试失败后进入退避
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error":{"message":"rate limited","type":"rate_limit_error"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	llm, err := openai.New(openai.WithBaseURL(srv.URL), openai.WithToken("test-token"), openai.WithModel("test-model"))
+	if err != nil {
+		t.Fatalf("openai.New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = TranslateWithAgentOptimized(ctx, llm, "Hello", "English", "Chinese")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("TranslateWithAgentOptimized() returned after %s, want prompt return on cancellation", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("TranslateWithAgentOptimized() error = %v, want context.Canceled", err)
+	}
+}
+
+// fakeChain 是返回固定输出的 chains.Chain，用于测试 chain 输出的类型转换
+type fakeChain struct {
+	output any
+}
+
+func (c fakeChain) Call(ctx context.Context, inputs map[string]any, options ...chains.ChainCallOption) (map[string]any, error) {
+	return map[string]any{"output": c.output}, nil
+}
+
+func (c fakeChain) GetMemory() schema.Memory { return memory.NewSimple() }
+func (c fakeChain) GetInputKeys() []string   { return []string{"input"} }
+func (c fakeChain) GetOutputKeys() []string  { return []string{"output"} }
+
+// stringerOutput 是实现了 fmt.Stringer 的输出类型
+type stringerOutput struct{ text string }
+
+func (s stringerOutput) String() string { return s.text }
+
+func TestRunChain_OutputTypes(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		output any
+		want   string
+	}{
+		{name: "String", output: "你好", want: "你好"},
+		{name: "Bytes", output: []byte("你好"), want: "你好"},
+		{name: "Stringer", output: stringerOutput{text: "你好"}, want: "你好"},
+		{name: "Text Content", output: llms.TextContent{Text: "你好"}, want: "你好"},
+		{name: "Content Choice", output: &llms.ContentChoice{Content: "你好"}, want: "你好"},
+		{name: "Message Content", output: llms.TextParts(llms.ChatMessageTypeAI, "你", "好"), want: "你好"},
+		{name: "Chat Message", output: llms.AIChatMessage{Content: "你好"}, want: "你好"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runChain(ctx, fakeChain{output: tt.output}, "Translate 'Hello'")
+			if err != nil {
+				t.Fatalf("runChain() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("runChain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunChain_UnsupportedOutput(t *testing.T) {
+	ctx := context.Background()
+	for _, output := range []any{42, map[string]string{"text": "你好"}, nil} {
+		_, err := runChain(ctx, fakeChain{output: output}, "Translate 'Hello'")
+		if !errors.Is(err, chains.ErrWrongOutputTypeInRun) {
+			t.Errorf("runChain(%T) error = %v, want ErrWrongOutputTypeInRun", output, err)
+			continue
+		}
+		if want := fmt.Sprintf("%T", output); !strings.Contains(err.Error(), want) {
+			t.Errorf("runChain(%T) error = %v, want the Go type %s in the message", output, err, want)
+		}
+	}
+}
+
+func TestAgentTools_Calculator(t *testing.T) {
+	llm := &fakeLLM{respond: func(string) (string, error) { return "", nil }}
+	names := func(o *options) []string {
+		var got []string
+		for _, tool := range agentTools(llm, o) {
+			got = append(got, tool.Name())
+		}
+		return got
+	}
+
+	if got := names(newOptions(nil)); len(got) != 1 || got[0] != translateToolName {
+		t.Errorf("default tools = %v, want only %s", got, translateToolName)
+	}
+	if got := names(newOptions([]Option{WithCalculator(true)})); len(got) != 2 || got[1] != (&tools.Calculator{}).Name() {
+		t.Errorf("tools with calculator = %v, want translator and calculator", got)
+	}
+}
+
+func TestTranslateWithAgentOptimized_CallbacksHandler(t *testing.T) {
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if !strings.Contains(prompt, "Question: Translate 'Hello'") {
+			return "你好", nil // 翻译工具内部的模型调用
+		}
+		if strings.Contains(prompt, "Observation: 你好") {
+			return "Thought: I now know the final answer.\nFinal Answer: 你好", nil
+		}
+		return "Thought: I should use the translation tool.\nAction: translate_text\nAction Input: Hello", nil
+	}}
+
+	spy := &actionSpy{}
+	got, err := TranslateWithAgentOptimized(ctx, llm, "Hello", "English", "Chinese", WithCallbacksHandler(spy), WithRequireTranslateTool())
+	if err != nil {
+		t.Fatalf("TranslateWithAgentOptimized() error = %v", err)
+	}
+	if strings.TrimSpace(got) != "你好" {
+		t.Errorf("TranslateWithAgentOptimized() = %q, want %q", got, "你好")
+	}
+	if len(spy.tools) == 0 || spy.tools[0] != "translate_text" {
+		t.Errorf("recorded tool calls = %v, want translate_text at least once", spy.tools)
+	}
+}
+
+func TestTranslateWithAgentOptimized_RequireTranslateTool(t *testing.T) {
+	orig := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = orig })
+
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Thought: I know the answer.\nFinal Answer: 你好", nil
+	}}
+
+	// 默认允许 agent 直接作答
+	if got, err := TranslateWithAgentOptimized(ctx, llm, "Hello", "English", "Chinese"); err != nil || strings.TrimSpace(got) != "你好" {
+		t.Fatalf("TranslateWithAgentOptimized() = %q, %v, want 你好", got, err)
+	}
+
+	_, err := TranslateWithAgentOptimized(ctx, llm, "Hello", "English", "Chinese", WithRequireTranslateTool())
+	if !errors.Is(err, ErrTranslateToolNotUsed) {
+		t.Errorf("TranslateWithAgentOptimized() error = %v, want ErrTranslateToolNotUsed", err)
+	}
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+// TranslateBatchWithAgent 用同一个 agent 执行器依次翻译多段文本，
+// 工具和执行器只初始化一次，分摊到所有文本上。结果与 texts 一一对应；
+// 重复的文本只翻译一次。任意一条失败时返回带索引的错误
+func TranslateBatchWithAgent(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]string, error) {
+	// 输入验证
+	if inputLanguage == "" {
+		return nil, translator.ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return nil, translator.ErrEmptyOutputLanguage
+	}
+	if llm == nil {
+		return nil, fmt.Errorf("LLM client is nil")
+	}
+	for i, text := range texts {
+		if text == "" {
+			return nil, fmt.Errorf("text at index %d: %w", i, translator.ErrEmptyText)
+		}
+	}
+
+	o := newOptions(opts)
+	executor := newAgentExecutor(llm, o, defaultMaxIterations)
+	results := make([]string, len(texts))
+	done := make(map[string]string, len(texts))
+	for i, text := range texts {
+		if result, ok := done[text]; ok {
+			results[i] = result
+			continue
+		}
+
+		// 每条文本单独限时，与 TranslateWithAgent 保持一致
+		itemCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		result, err := runExecutor(itemCtx, executor, text, inputLanguage, outputLanguage, o)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate text at index %d: %w", i, err)
+		}
+
+		result = strings.TrimSpace(result)
+		results[i] = result
+		done[text] = result
+	}
+	return results, nil
+}
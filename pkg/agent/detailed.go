@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+// AgentResult 是 agent 翻译的详细结果
+type AgentResult struct {
+	Text       string   // 译文
+	Iterations int      // agent 的规划步数：每次工具调用计一步，给出最终答案再计一步
+	ToolsUsed  []string // 按调用顺序排列的工具名称，同一工具多次调用时重复出现
+}
+
+// TranslateWithAgentDetailed 与 TranslateWithAgent 相同，但同时返回 agent 调用了哪些工具以及用了多少步，
+// 便于排查 agent 的行为。出错时返回已记录的部分结果
+func TranslateWithAgentDetailed(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (AgentResult, error) {
+	// 添加超时控制，避免长时间阻塞
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	// 输入验证
+	if text == "" {
+		return AgentResult{}, translator.ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return AgentResult{}, translator.ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return AgentResult{}, translator.ErrEmptyOutputLanguage
+	}
+	if llm == nil {
+		return AgentResult{}, fmt.Errorf("LLM client is nil")
+	}
+
+	// 本次运行单独记录工具调用，同时保留用户设置的回调处理器
+	o := newOptions(opts)
+	stats := &runStats{}
+	if o.callbacks != nil {
+		o.callbacks = callbacks.CombiningHandler{Callbacks: []callbacks.Handler{o.callbacks, stats}}
+	} else {
+		o.callbacks = stats
+	}
+
+	result, err := runAgent(ctx, llm, text, inputLanguage, outputLanguage, o)
+	detailed := AgentResult{ToolsUsed: stats.toolsUsed()}
+	detailed.Iterations = len(detailed.ToolsUsed)
+	if err != nil {
+		return detailed, err
+	}
+	detailed.Text = strings.TrimSpace(result)
+	detailed.Iterations++
+	return detailed, nil
+}
+
+// runStats 记录一次 agent 运行中调用的工具
+type runStats struct {
+	callbacks.SimpleHandler
+
+	mu    sync.Mutex
+	tools []string
+}
+
+// HandleAgentAction 记录 agent 选择的工具
+func (s *runStats) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = append(s.tools, strings.TrimSpace(action.Tool))
+}
+
+// toolsUsed 返回目前为止记录的工具名称
+func (s *runStats) toolsUsed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.tools...)
+}
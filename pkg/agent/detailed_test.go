@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTranslateWithAgentDetailed(t *testing.T) {
+	// agent 先调用一次不存在的工具，再调用翻译工具，拿到译文后给出最终答案
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if !strings.Contains(prompt, "Question: Translate 'Hello'") {
+			return "你好", nil // 翻译工具内部的模型调用
+		}
+		switch {
+		case strings.Contains(prompt, "Observation: 你好"):
+			return "Thought: I now know the final answer.\nFinal Answer: 你好", nil
+		case strings.Contains(prompt, "is not a valid tool"):
+			return "Thought: I should use the translation tool.\nAction: translate_text\nAction Input: Hello", nil
+		default:
+			return "Thought: Let me look it up.\nAction: dictionary\nAction Input: Hello", nil
+		}
+	}}
+
+	spy := &actionSpy{}
+	got, err := TranslateWithAgentDetailed(context.Background(), llm, "Hello", "English", "Chinese", WithCallbacksHandler(spy))
+	if err != nil {
+		t.Fatalf("TranslateWithAgentDetailed() error = %v", err)
+	}
+	want := AgentResult{Text: "你好", Iterations: 3, ToolsUsed: []string{"dictionary", translateToolName}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateWithAgentDetailed() = %+v, want %+v", got, want)
+	}
+
+	// 用户的回调处理器仍然收到工具调用
+	if !reflect.DeepEqual(spy.tools, want.ToolsUsed) {
+		t.Errorf("user handler saw tools %v, want %v", spy.tools, want.ToolsUsed)
+	}
+}
+
+func TestTranslateWithAgentDetailed_DirectAnswer(t *testing.T) {
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Thought: I know the answer.\nFinal Answer: 你好", nil
+	}}
+
+	got, err := TranslateWithAgentDetailed(context.Background(), llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateWithAgentDetailed() error = %v", err)
+	}
+	if got.Text != "你好" || got.Iterations != 1 || len(got.ToolsUsed) != 0 {
+		t.Errorf("TranslateWithAgentDetailed() = %+v, want one iteration without tools", got)
+	}
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+// TranslationPath 表示最终产生译文的翻译路径
+type TranslationPath string
+
+const (
+	// PathAgent 表示译文来自 agent 执行器
+	PathAgent TranslationPath = "agent"
+	// PathDirect 表示 agent 失败后由 translator.Translate 直接翻译
+	PathDirect TranslationPath = "direct"
+)
+
+// TranslateWithAgentFallback 先尝试 agent 翻译；agent 出错、超出迭代次数或没有给出结果时，
+// 回退到更轻量的 translator.Translate，并返回产生译文的路径。
+// 认证失败时两条路径都不可能成功，直接返回错误
+func TranslateWithAgentFallback(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, TranslationPath, error) {
+	// 输入验证
+	if text == "" {
+		return "", "", translator.ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return "", "", translator.ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return "", "", translator.ErrEmptyOutputLanguage
+	}
+	if llm == nil {
+		return "", "", fmt.Errorf("LLM client is nil")
+	}
+
+	// agent 单独限时，超时后仍留有时间走直接翻译
+	agentCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	result, err := runAgent(agentCtx, llm, text, inputLanguage, outputLanguage, newOptions(opts))
+	cancel()
+	result = strings.TrimSpace(result)
+	if err == nil && result != "" {
+		return result, PathAgent, nil
+	}
+
+	if err != nil {
+		if errors.Is(err, translator.ErrUnauthorized) || ctx.Err() != nil {
+			return "", PathAgent, err
+		}
+		log.Printf("Agent translation failed, falling back to direct translation: %v", err)
+	} else {
+		log.Printf("Agent returned an empty result, falling back to direct translation")
+	}
+
+	result, err = translator.Translate(ctx, llm, text, inputLanguage, outputLanguage)
+	if err != nil {
+		return "", PathDirect, fmt.Errorf("direct translation fallback failed: %w", err)
+	}
+	return result, PathDirect, nil
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+)
+
+// agent 提示词中工具说明和问题部分的模板，与 langchaingo 默认的 MRKL 提示词保持一致
+const (
+	toolDescriptionsPrompt = `You have access to the following tools:
+
+{{.tool_descriptions}}`
+
+	questionPrompt = `Begin!
+
+Question: {{.input}}
+{{.agent_scratchpad}}`
+)
+
+// Option 是 agent 翻译的可选配置
+type Option func(*options)
+
+// options 保存 agent 翻译的配置
+type options struct {
+	promptPrefix string // 放在 agent 提示词开头的指令
+	promptSuffix string // 放在问题之前的指令
+
+	callbacks            callbacks.Handler // agent 和执行器的回调处理器
+	requireTranslateTool bool              // agent 没有调用翻译工具时是否返回错误
+
+	calculator bool // 工具列表中是否包含计算器
+}
+
+// newOptions 根据传入的 Option 构建配置
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPromptPrefix 设置放在 agent 提示词开头的指令，例如 "Only use the translate_text tool"，
+// 用于减少 agent 选错工具的情况。工具说明会自动追加在指令之后。
+// 指令按 Go 模板渲染，可以使用 {{.today}}
+func WithPromptPrefix(prefix string) Option {
+	return func(o *options) {
+		o.promptPrefix = prefix
+	}
+}
+
+// WithPromptSuffix 设置紧挨在问题之前的指令，例如要求最终答案只包含译文
+func WithPromptSuffix(suffix string) Option {
+	return func(o *options) {
+		o.promptSuffix = suffix
+	}
+}
+
+// WithCallbacksHandler 设置 agent 执行过程的回调处理器，可以观察模型调用、工具调用（HandleAgentAction）和最终答案
+func WithCallbacksHandler(handler callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacks = handler
+	}
+}
+
+// WithRequireTranslateTool 要求 agent 至少调用一次 translate_text 工具，
+// 否则返回 ErrTranslateToolNotUsed。agent 直接凭模型作答时会绕过缓存和工具的处理逻辑，
+// 开启后可以及早发现提示词配置不当的问题
+func WithRequireTranslateTool() Option {
+	return func(o *options) {
+		o.requireTranslateTool = true
+	}
+}
+
+// WithCalculator 设置 agent 的工具列表是否包含计算器，默认只提供翻译工具。
+// 纯翻译任务用不到计算器，多一个工具反而可能让 agent 选错
+func WithCalculator(enabled bool) Option {
+	return func(o *options) {
+		o.calculator = enabled
+	}
+}
+
+// agentOptions 把配置转换为 agent 初始化选项，未设置的部分使用 langchaingo 的默认提示词
+func (o *options) agentOptions() []agents.Option {
+	var agentOpts []agents.Option
+	if o.promptPrefix != "" {
+		agentOpts = append(agentOpts, agents.WithPromptPrefix(o.promptPrefix+"\n\n"+toolDescriptionsPrompt))
+	}
+	if o.promptSuffix != "" {
+		agentOpts = append(agentOpts, agents.WithPromptSuffix(o.promptSuffix+"\n\n"+questionPrompt))
+	}
+	return agentOpts
+}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// runChain 与 chains.Run 相同，用单个输入执行 chain 并返回唯一的输出，
+// 但输出不是 string 时会尝试用 chainOutputText 转换，而不是直接返回不带细节的 ErrWrongOutputTypeInRun
+func runChain(ctx context.Context, c chains.Chain, input string) (string, error) {
+	inputKeys := c.GetInputKeys()
+	if len(inputKeys) != 1 {
+		return "", chains.ErrMultipleInputsInRun
+	}
+	outputKeys := c.GetOutputKeys()
+	if len(outputKeys) != 1 {
+		return "", chains.ErrMultipleOutputsInRun
+	}
+
+	outputValues, err := chains.Call(ctx, c, map[string]any{inputKeys[0]: input})
+	if err != nil {
+		return "", err
+	}
+	return chainOutputText(outputValues[outputKeys[0]])
+}
+
+// chainOutputText 把 chain 的输出转换为文本，支持 string、[]byte、模型回复和消息类型以及 fmt.Stringer。
+// 无法转换时返回包含实际 Go 类型的 ErrWrongOutputTypeInRun
+func chainOutputText(v any) (string, error) {
+	switch out := v.(type) {
+	case string:
+		return out, nil
+	case []byte:
+		return string(out), nil
+	case llms.TextContent:
+		return out.Text, nil
+	case *llms.ContentChoice:
+		if out != nil {
+			return out.Content, nil
+		}
+	case llms.ContentChoice:
+		return out.Content, nil
+	case llms.MessageContent:
+		var parts []string
+		for _, part := range out.Parts {
+			text, ok := part.(llms.TextContent)
+			if !ok {
+				return "", fmt.Errorf("%w: message part of type %T is not text", chains.ErrWrongOutputTypeInRun, part)
+			}
+			parts = append(parts, text.Text)
+		}
+		return strings.Join(parts, ""), nil
+	case llms.ChatMessage:
+		return out.GetContent(), nil
+	case fmt.Stringer:
+		return out.String(), nil
+	}
+	return "", fmt.Errorf("%w: got %T (%v)", chains.ErrWrongOutputTypeInRun, v, v)
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+// ErrUnknownTool 表示 BuildTools 请求的工具没有注册
+var ErrUnknownTool = errors.New("unknown tool")
+
+// ToolFactory 用模型创建一个工具实例
+type ToolFactory func(llm llms.Model) tools.Tool
+
+// toolRegistry 保存按名称注册的工具工厂，默认包含翻译工具和计算器
+var toolRegistry = struct {
+	mu        sync.RWMutex
+	factories map[string]ToolFactory
+}{factories: map[string]ToolFactory{
+	translateToolName:            func(llm llms.Model) tools.Tool { return translator.NewTranslator(llm) },
+	(&tools.Calculator{}).Name(): func(llms.Model) tools.Tool { return &tools.Calculator{} },
+}}
+
+// RegisterTool 按名称注册工具工厂，之后可以通过 BuildTools 按名称组装 agent 的工具列表。
+// 名称区分大小写，通常与工具的 Name() 一致；重复注册会覆盖之前的工厂，factory 为 nil 时删除该名称
+func RegisterTool(name string, factory ToolFactory) {
+	toolRegistry.mu.Lock()
+	defer toolRegistry.mu.Unlock()
+	if factory == nil {
+		delete(toolRegistry.factories, name)
+		return
+	}
+	toolRegistry.factories[name] = factory
+}
+
+// BuildTools 按给定的名称和顺序创建工具，每次调用都会创建新的实例。
+// 任一名称未注册时返回 ErrUnknownTool
+func BuildTools(llm llms.Model, names ...string) ([]tools.Tool, error) {
+	toolRegistry.mu.RLock()
+	factories := make([]ToolFactory, len(names))
+	for i, name := range names {
+		factory, ok := toolRegistry.factories[name]
+		if !ok {
+			toolRegistry.mu.RUnlock()
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+		}
+		factories[i] = factory
+	}
+	toolRegistry.mu.RUnlock()
+
+	toolList := make([]tools.Tool, len(factories))
+	for i, factory := range factories {
+		toolList[i] = factory(llm)
+	}
+	return toolList, nil
+}
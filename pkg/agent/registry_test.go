@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// dictionaryTool 是测试用的工具，记录创建它时使用的模型
+type dictionaryTool struct {
+	llm llms.Model
+}
+
+func (d *dictionaryTool) Name() string        { return "dictionary" }
+func (d *dictionaryTool) Description() string { return "Looks up a word in the dictionary." }
+func (d *dictionaryTool) Call(ctx context.Context, input string) (string, error) {
+	return "definition of " + input, nil
+}
+
+func TestRegisterTool_BuildTools(t *testing.T) {
+	RegisterTool("dictionary", func(llm llms.Model) tools.Tool { return &dictionaryTool{llm: llm} })
+	t.Cleanup(func() { RegisterTool("dictionary", nil) })
+
+	llm := &fakeLLM{respond: func(string) (string, error) { return "", nil }}
+	toolList, err := BuildTools(llm, "dictionary", translateToolName, "calculator")
+	if err != nil {
+		t.Fatalf("BuildTools() error = %v", err)
+	}
+
+	want := []string{"dictionary", translateToolName, "calculator"}
+	if len(toolList) != len(want) {
+		t.Fatalf("BuildTools() returned %d tools, want %d", len(toolList), len(want))
+	}
+	for i, tool := range toolList {
+		if tool.Name() != want[i] {
+			t.Errorf("tool %d = %s, want %s", i, tool.Name(), want[i])
+		}
+	}
+	if d, ok := toolList[0].(*dictionaryTool); !ok || d.llm != llm {
+		t.Errorf("dictionary tool was not built with the given model: %#v", toolList[0])
+	}
+
+	// 删除后不能再按名称创建
+	RegisterTool("dictionary", nil)
+	if _, err := BuildTools(llm, "dictionary"); !errors.Is(err, ErrUnknownTool) {
+		t.Errorf("BuildTools() error = %v, want ErrUnknownTool", err)
+	}
+}
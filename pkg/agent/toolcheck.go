@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/costa92/langchaingo-demo/pkg/translator"
+)
+
+// ErrTranslateToolNotUsed 表示 agent 没有调用翻译工具就给出了答案
+var ErrTranslateToolNotUsed = errors.New("agent answered without using the translate tool")
+
+// translateToolName 是翻译工具的名称，与 translator.Translator.Name() 一致
+var translateToolName = (&translator.Translator{}).Name()
+
+// toolRecorder 统计 agent 调用翻译工具的次数，并把所有回调转发给用户设置的处理器
+type toolRecorder struct {
+	callbacks.Handler
+	translateCalls atomic.Int64
+}
+
+// newToolRecorder 创建转发到 next 的 toolRecorder，next 可以为 nil
+func newToolRecorder(next callbacks.Handler) *toolRecorder {
+	if next == nil {
+		next = callbacks.SimpleHandler{}
+	}
+	return &toolRecorder{Handler: next}
+}
+
+// HandleAgentAction 记录翻译工具调用后转发给下一个处理器。
+// 执行器按名称查找工具时不区分大小写，这里保持一致
+func (r *toolRecorder) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	if strings.EqualFold(strings.TrimSpace(action.Tool), translateToolName) {
+		r.translateCalls.Add(1)
+	}
+	r.Handler.HandleAgentAction(ctx, action)
+}
+
+// calls 返回目前为止翻译工具被调用的次数
+func (r *toolRecorder) calls() int64 {
+	return r.translateCalls.Load()
+}
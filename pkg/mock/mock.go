@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/tools"
@@ -13,6 +14,8 @@ import (
 // MockTranslator 实现模拟翻译器用于测试
 type MockTranslator struct {
 	CallbacksHandler callbacks.Handler
+	// Delay 模拟真实工具的调用耗时，期间可被 ctx 取消
+	Delay time.Duration
 }
 
 // NewMockTranslator 创建一个新的模拟翻译器
@@ -23,6 +26,10 @@ func NewMockTranslator() *MockTranslator {
 func (m *MockTranslator) Call(ctx context.Context, input string) (string, error) {
 	log.Printf("MockTranslator tool called with input: %s", input)
 
+	if err := simulateDelay(ctx, m.Delay); err != nil {
+		return "", err
+	}
+
 	if m.CallbacksHandler != nil {
 		m.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -63,6 +70,8 @@ var _ tools.Tool = (*MockTranslator)(nil)
 // MockCalculator 实现模拟计算器用于测试
 type MockCalculator struct {
 	CallbacksHandler callbacks.Handler
+	// Delay 模拟真实工具的调用耗时，期间可被 ctx 取消
+	Delay time.Duration
 }
 
 // NewMockCalculator 创建一个新的模拟计算器
@@ -73,6 +82,10 @@ func NewMockCalculator() *MockCalculator {
 func (m *MockCalculator) Call(ctx context.Context, input string) (string, error) {
 	log.Printf("MockCalculator tool called with input: %s", input)
 
+	if err := simulateDelay(ctx, m.Delay); err != nil {
+		return "", err
+	}
+
 	if m.CallbacksHandler != nil {
 		m.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -112,6 +125,25 @@ func (m *MockCalculator) Name() string {
 // 确保 MockCalculator 实现了 tools.Tool 接口
 var _ tools.Tool = (*MockCalculator)(nil)
 
+// simulateDelay 在调用前后检查 ctx，并等待 d 模拟调用耗时；ctx 被取消时返回 ctx.Err()
+func simulateDelay(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	return ctx.Err()
+}
+
 // RunMockTests 运行所有模拟测试
 func RunMockTests() {
 	ctx := context.Background()
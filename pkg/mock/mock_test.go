@@ -2,7 +2,11 @@ package mock
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/tools"
 )
 
 func TestMockTranslator_Call(t *testing.T) {
@@ -93,6 +97,41 @@ func TestMockCalculator_Call(t *testing.T) {
 	}
 }
 
+func TestMockTools_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tool := range []tools.Tool{NewMockTranslator(), NewMockCalculator()} {
+		got, err := tool.Call(ctx, "hello world")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s.Call() error = %v, want context.Canceled", tool.Name(), err)
+		}
+		if got != "" {
+			t.Errorf("%s.Call() = %q, want empty result", tool.Name(), got)
+		}
+	}
+}
+
+func TestMockTools_CancelledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	tools := []tools.Tool{
+		&MockTranslator{Delay: time.Second},
+		&MockCalculator{Delay: time.Second},
+	}
+	for _, tool := range tools {
+		start := time.Now()
+		_, err := tool.Call(ctx, "2 + 3")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s.Call() error = %v, want context.DeadlineExceeded", tool.Name(), err)
+		}
+		if elapsed := time.Since(start); elapsed >= time.Second {
+			t.Errorf("%s.Call() took %s, want it to return on cancellation", tool.Name(), elapsed)
+		}
+	}
+}
+
 func TestMockTools_Interface(t *testing.T) {
 	// 测试翻译器接口
 	translator := NewMockTranslator()
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/costa92/langchaingo-demo/pkg/retrybudget"
+)
+
+// 重试退避的默认参数
+const (
+	defaultRetryBackoff = 200 * time.Millisecond // 第一次重试前的等待时间，之后每次翻倍
+	maxRetryWait        = 30 * time.Second       // 单次等待的上限，包括 Retry-After 指定的时间
+)
+
+// NewRetryingHTTPClient 创建带超时和自动重试的 HTTP 客户端。
+// 429、502、503、504 响应表示服务端未处理请求，任何方法都会重试；
+// 连接错误只对幂等方法（GET、HEAD、OPTIONS、PUT、DELETE）重试。
+// 重试间隔按指数退避增长，响应带有 Retry-After 时以它为准。timeout 覆盖包括重试在内的整个请求。
+// 请求的 context 带有 retrybudget.With（或 translator.WithRetryBudget）设置的预算时，每次重试都从中扣除，预算用完后不再重试
+func NewRetryingHTTPClient(timeout time.Duration, maxRetries int) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &retryTransport{
+			base:       http.DefaultTransport,
+			maxRetries: maxRetries,
+			backoff:    defaultRetryBackoff,
+		},
+	}
+}
+
+// retryTransport 是按退避策略重试失败请求的 http.RoundTripper
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+// RoundTrip 执行请求，失败且可重试时等待后重新发送
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// 与翻译流程中的其他重试点共享请求 context 中的重试预算
+		if !retrybudget.Spend(req.Context()) {
+			return resp, err
+		}
+
+		// 请求体已被读取，需要能够重新获取才能重发
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		wait := t.backoff << attempt
+		if resp != nil {
+			if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = after
+			}
+			// 丢弃响应体以便复用连接
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retry aborted: %w", req.Context().Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry 判断一次请求的结果是否值得重试
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		return isIdempotent(req.Method)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotent 判断 HTTP 方法是否幂等
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, "":
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
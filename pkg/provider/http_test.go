@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/costa92/langchaingo-demo/pkg/retrybudget"
+)
+
+// newFlakyServer 返回一个前 failures 次请求响应 status、之后响应 200 的测试服务器，并记录请求次数和请求体
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32, *[]string) {
+	t.Helper()
+	var calls atomic.Int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls, &bodies
+}
+
+// newTestClient 创建退避时间很短的重试客户端，避免测试变慢
+func newTestClient(maxRetries int) *http.Client {
+	client := NewRetryingHTTPClient(5*time.Second, maxRetries)
+	client.Transport.(*retryTransport).backoff = time.Millisecond
+	return client
+}
+
+func TestRetryingHTTPClient_RetriesThenSucceeds(t *testing.T) {
+	srv, calls, bodies := newFlakyServer(t, 1, http.StatusServiceUnavailable, "0")
+
+	// POST 请求在 503 后重试，并重新发送完整的请求体
+	resp, err := newTestClient(2).Post(srv.URL, "application/json", strings.NewReader(`{"q":"hello"}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+	for i, body := range *bodies {
+		if body != `{"q":"hello"}` {
+			t.Errorf("request %d body = %q, want original body", i+1, body)
+		}
+	}
+}
+
+func TestRetryingHTTPClient_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, calls, _ := newFlakyServer(t, 10, http.StatusTooManyRequests, "")
+
+	resp, err := newTestClient(2).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3 (1 + 2 retries)", got)
+	}
+}
+
+func TestRetryingHTTPClient_NoRetryOnClientError(t *testing.T) {
+	srv, calls, _ := newFlakyServer(t, 10, http.StatusBadRequest, "")
+
+	resp, err := newTestClient(2).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestRetryingHTTPClient_RespectsRetryAfter(t *testing.T) {
+	srv, calls, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, "1")
+
+	start := time.Now()
+	resp, err := newTestClient(1).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "3", want: 3 * time.Second, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "Mon, 01 Jan 2024 00:00:05 GMT", want: 5 * time.Second, wantOK: true},
+		{value: "Sun, 31 Dec 2023 23:59:00 GMT", want: 0, wantOK: true},
+		{value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryingHTTPClient_RetryBudget(t *testing.T) {
+	srv, calls, _ := newFlakyServer(t, 10, http.StatusServiceUnavailable, "0")
+	client := newTestClient(5)
+
+	// 预算为 1 时只重试一次，即使 maxRetries 更大
+	ctx := retrybudget.With(context.Background(), 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2 (1 + 1 budgeted retry)", got)
+	}
+	if retrybudget.Spend(ctx) {
+		t.Error("SpendRetry() after the request = true, want the budget to be used up")
+	}
+}
@@ -0,0 +1,187 @@
+// Package provider 根据配置或环境变量构造翻译使用的 LLM 客户端，
+// 支持 OpenAI 兼容接口（如 SiliconFlow）和本地运行的 Ollama 模型。
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// 支持的模型提供方
+const (
+	ProviderOpenAI = "openai" // OpenAI 兼容接口
+	ProviderOllama = "ollama" // 本地 Ollama 服务
+)
+
+// NewLLMFromEnv 读取的环境变量
+const (
+	EnvProvider    = "LLM_PROVIDER"        // 显式指定提供方：openai 或 ollama
+	EnvModel       = "LLM_MODEL"           // 模型名称，未设置时使用提供方的默认模型
+	EnvAPIKey      = "SILICONFLOW_API_KEY" // OpenAI 兼容接口的 API Key
+	EnvAPIURL      = "SILICONFLOW_API_URL" // OpenAI 兼容接口的地址
+	EnvOllamaHost  = "OLLAMA_HOST"         // Ollama 服务地址
+	EnvOllamaModel = "OLLAMA_MODEL"        // Ollama 模型名称，优先于 LLM_MODEL
+)
+
+// 各提供方的默认配置
+const (
+	defaultAPIURL      = "https://api.siliconflow.cn/v1"
+	defaultModel       = "Qwen/Qwen3-30B-A3B"
+	defaultOllamaHost  = "http://localhost:11434"
+	defaultOllamaModel = "llama3"
+	defaultTimeout     = 60 * time.Second
+	defaultMaxRetries  = 2
+)
+
+// LLMConfig 描述如何构造 LLM 客户端
+type LLMConfig struct {
+	Provider   string        // 提供方：openai 或 ollama，为空时使用 openai
+	BaseURL    string        // 服务地址，为空时使用提供方的默认地址
+	Model      string        // 模型名称（必填）
+	APIKeyEnv  string        // 保存 API Key 的环境变量名，openai 提供方必填
+	Timeout    time.Duration // HTTP 请求超时（含重试），0 表示使用默认的 60 秒
+	MaxRetries int           // 429/5xx 等临时失败的最大重试次数，0 表示不重试
+
+	// HTTPClient 是发送请求使用的客户端，可用于配置代理、自定义 TLS 或接入链路追踪。
+	// 为 nil 时使用 NewRetryingHTTPClient(Timeout, MaxRetries)；设置后 Timeout 和 MaxRetries 不生效
+	HTTPClient *http.Client
+}
+
+// NewLLM 校验配置并创建对应提供方的客户端。未设置 cfg.HTTPClient 时，HTTP 请求带有
+// cfg.Timeout 的超时，并按 cfg.MaxRetries 重试临时失败
+func NewLLM(cfg LLMConfig) (llms.Model, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("empty model name")
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("negative timeout %s", cfg.Timeout)
+	}
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("negative max retries %d", cfg.MaxRetries)
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = NewRetryingHTTPClient(timeout, cfg.MaxRetries)
+	}
+
+	switch provider := strings.ToLower(strings.TrimSpace(cfg.Provider)); provider {
+	case ProviderOpenAI, "":
+		if cfg.APIKeyEnv == "" {
+			return nil, fmt.Errorf("empty API key environment variable name")
+		}
+		apiKey := os.Getenv(cfg.APIKeyEnv)
+		if apiKey == "" {
+			return nil, fmt.Errorf("%s not set", cfg.APIKeyEnv)
+		}
+		llm, err := openai.New(
+			openai.WithModel(cfg.Model),
+			openai.WithBaseURL(firstNonEmpty(cfg.BaseURL, defaultAPIURL)),
+			openai.WithToken(apiKey),
+			openai.WithHTTPClient(httpClient),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create openai client: %w", err)
+		}
+		return llm, nil
+	case ProviderOllama:
+		llm, err := ollama.New(
+			ollama.WithServerURL(firstNonEmpty(cfg.BaseURL, defaultOllamaHost)),
+			ollama.WithModel(cfg.Model),
+			ollama.WithHTTPClient(httpClient),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ollama client: %w", err)
+		}
+		return llm, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.Provider)
+	}
+}
+
+// NewLocalLLM 创建连接本地 Ollama 服务的客户端，baseURL 为空时使用 http://localhost:11434
+func NewLocalLLM(baseURL, model string) (*ollama.LLM, error) {
+	if model == "" {
+		return nil, fmt.Errorf("empty model name")
+	}
+	if baseURL == "" {
+		baseURL = defaultOllamaHost
+	}
+	llm, err := ollama.New(
+		ollama.WithServerURL(baseURL),
+		ollama.WithModel(model),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ollama client: %w", err)
+	}
+	return llm, nil
+}
+
+// NewLLMFromEnv 根据环境变量选择提供方并创建客户端。
+// 设置了 LLM_PROVIDER 时以它为准；否则有 SILICONFLOW_API_KEY 时使用 OpenAI 兼容接口，
+// 只有 OLLAMA_HOST 时使用 Ollama；两者都没有时返回错误
+func NewLLMFromEnv() (llms.Model, error) {
+	cfg, err := configFromEnv(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return NewLLM(cfg)
+}
+
+// configFromEnv 用 getenv 读取环境变量并决定提供方配置，不发起任何网络请求
+func configFromEnv(getenv func(string) string) (LLMConfig, error) {
+	provider := strings.ToLower(strings.TrimSpace(getenv(EnvProvider)))
+	if provider == "" {
+		switch {
+		case getenv(EnvAPIKey) != "":
+			provider = ProviderOpenAI
+		case getenv(EnvOllamaHost) != "":
+			provider = ProviderOllama
+		default:
+			return LLMConfig{}, fmt.Errorf("no LLM provider configured: set %s or %s", EnvAPIKey, EnvOllamaHost)
+		}
+	}
+
+	switch provider {
+	case ProviderOpenAI:
+		if getenv(EnvAPIKey) == "" {
+			return LLMConfig{}, fmt.Errorf("%s not set", EnvAPIKey)
+		}
+		return LLMConfig{
+			Provider:   ProviderOpenAI,
+			BaseURL:    firstNonEmpty(getenv(EnvAPIURL), defaultAPIURL),
+			Model:      firstNonEmpty(getenv(EnvModel), defaultModel),
+			APIKeyEnv:  EnvAPIKey,
+			MaxRetries: defaultMaxRetries,
+		}, nil
+	case ProviderOllama:
+		return LLMConfig{
+			Provider:   ProviderOllama,
+			BaseURL:    firstNonEmpty(getenv(EnvOllamaHost), defaultOllamaHost),
+			Model:      firstNonEmpty(getenv(EnvOllamaModel), getenv(EnvModel), defaultOllamaModel),
+			MaxRetries: defaultMaxRetries,
+		}, nil
+	default:
+		return LLMConfig{}, fmt.Errorf("unknown LLM provider %q", provider)
+	}
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		want          LLMConfig
+		errorContains string
+	}{
+		{
+			name: "OpenAI From API Key",
+			env:  map[string]string{EnvAPIKey: "sk-test"},
+			want: LLMConfig{Provider: ProviderOpenAI, BaseURL: defaultAPIURL, Model: defaultModel, APIKeyEnv: EnvAPIKey, MaxRetries: defaultMaxRetries},
+		},
+		{
+			name: "OpenAI Custom URL And Model",
+			env:  map[string]string{EnvAPIKey: "sk-test", EnvAPIURL: "https://example.com/v1", EnvModel: "gpt-4o"},
+			want: LLMConfig{Provider: ProviderOpenAI, BaseURL: "https://example.com/v1", Model: "gpt-4o", APIKeyEnv: EnvAPIKey, MaxRetries: defaultMaxRetries},
+		},
+		{
+			name: "Ollama From Host",
+			env:  map[string]string{EnvOllamaHost: "http://gpu-box:11434"},
+			want: LLMConfig{Provider: ProviderOllama, BaseURL: "http://gpu-box:11434", Model: defaultOllamaModel, MaxRetries: defaultMaxRetries},
+		},
+		{
+			name: "API Key Wins Without Explicit Provider",
+			env:  map[string]string{EnvAPIKey: "sk-test", EnvOllamaHost: "http://gpu-box:11434"},
+			want: LLMConfig{Provider: ProviderOpenAI, BaseURL: defaultAPIURL, Model: defaultModel, APIKeyEnv: EnvAPIKey, MaxRetries: defaultMaxRetries},
+		},
+		{
+			name: "Explicit Ollama Provider",
+			env:  map[string]string{EnvProvider: "Ollama", EnvAPIKey: "sk-test", EnvModel: "qwen2", EnvOllamaModel: "llama3.1"},
+			want: LLMConfig{Provider: ProviderOllama, BaseURL: defaultOllamaHost, Model: "llama3.1", MaxRetries: defaultMaxRetries},
+		},
+		{
+			name: "Ollama Falls Back To LLM_MODEL",
+			env:  map[string]string{EnvProvider: "ollama", EnvModel: "qwen2"},
+			want: LLMConfig{Provider: ProviderOllama, BaseURL: defaultOllamaHost, Model: "qwen2", MaxRetries: defaultMaxRetries},
+		},
+		{
+			name:          "Explicit OpenAI Without Key",
+			env:           map[string]string{EnvProvider: "openai"},
+			errorContains: EnvAPIKey,
+		},
+		{
+			name:          "Unknown Provider",
+			env:           map[string]string{EnvProvider: "bedrock"},
+			errorContains: "unknown LLM provider",
+		},
+		{
+			name:          "Nothing Configured",
+			env:           map[string]string{},
+			errorContains: "no LLM provider configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configFromEnv(func(key string) string { return tt.env[key] })
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Fatalf("configFromEnv() error = %v, want error containing %q", err, tt.errorContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("configFromEnv() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("configFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLocalLLM(t *testing.T) {
+	if _, err := NewLocalLLM("", ""); err == nil {
+		t.Error("expected error for empty model")
+	}
+	// 创建客户端不会连接服务
+	if llm, err := NewLocalLLM("", "llama3"); err != nil || llm == nil {
+		t.Errorf("NewLocalLLM() = %v, %v, want client", llm, err)
+	}
+}
+
+func TestNewLLMFromEnv(t *testing.T) {
+	t.Setenv(EnvProvider, "")
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv(EnvOllamaHost, "http://localhost:11434")
+
+	llm, err := NewLLMFromEnv()
+	if err != nil {
+		t.Fatalf("NewLLMFromEnv() error = %v", err)
+	}
+	if llm == nil {
+		t.Fatal("NewLLMFromEnv() returned nil client")
+	}
+}
+
+func TestNewLLM(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-test")
+	t.Setenv("TEST_LLM_EMPTY_KEY", "")
+
+	tests := []struct {
+		name          string
+		cfg           LLMConfig
+		errorContains string
+	}{
+		{name: "OpenAI", cfg: LLMConfig{Provider: ProviderOpenAI, Model: "gpt-4o", APIKeyEnv: "TEST_LLM_API_KEY", Timeout: 5 * time.Second}},
+		{name: "Default Provider", cfg: LLMConfig{Model: "gpt-4o", APIKeyEnv: "TEST_LLM_API_KEY"}},
+		{name: "Ollama", cfg: LLMConfig{Provider: "Ollama", BaseURL: "http://gpu-box:11434", Model: "llama3"}},
+		{name: "Missing Model", cfg: LLMConfig{Provider: ProviderOpenAI, APIKeyEnv: "TEST_LLM_API_KEY"}, errorContains: "empty model name"},
+		{name: "Unknown Provider", cfg: LLMConfig{Provider: "bedrock", Model: "claude"}, errorContains: "unknown LLM provider"},
+		{name: "Missing API Key Env Name", cfg: LLMConfig{Provider: ProviderOpenAI, Model: "gpt-4o"}, errorContains: "API key environment variable"},
+		{name: "API Key Env Not Set", cfg: LLMConfig{Provider: ProviderOpenAI, Model: "gpt-4o", APIKeyEnv: "TEST_LLM_EMPTY_KEY"}, errorContains: "TEST_LLM_EMPTY_KEY not set"},
+		{name: "Negative Max Retries", cfg: LLMConfig{Provider: ProviderOllama, Model: "llama3", MaxRetries: -1}, errorContains: "negative max retries"},
+		{name: "Negative Timeout", cfg: LLMConfig{Provider: ProviderOllama, Model: "llama3", Timeout: -time.Second}, errorContains: "negative timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm, err := NewLLM(tt.cfg)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Fatalf("NewLLM() error = %v, want error containing %q", err, tt.errorContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLLM() error = %v", err)
+			}
+			if llm == nil {
+				t.Fatal("NewLLM() returned nil client")
+			}
+		})
+	}
+}
+
+// countingTransport 记录经过的请求数后交给 http.DefaultTransport 发送
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewLLM_CustomHTTPClient(t *testing.T) {
+	t.Setenv("TEST_LLM_API_KEY", "sk-test")
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"1","object":"chat.completion","created":0,"model":"gpt-4o",
+"choices":[{"index":0,"message":{"role":"assistant","content":"你好"},"finish_reason":"stop"}],
+"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer srv.Close()
+
+	transport := &countingTransport{}
+	llm, err := NewLLM(LLMConfig{
+		Provider:   ProviderOpenAI,
+		BaseURL:    srv.URL,
+		Model:      "gpt-4o",
+		APIKeyEnv:  "TEST_LLM_API_KEY",
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("NewLLM() error = %v", err)
+	}
+
+	out, err := llms.GenerateFromSinglePrompt(context.Background(), llm, "Translate Hello")
+	if err != nil {
+		t.Fatalf("GenerateFromSinglePrompt() error = %v", err)
+	}
+	if out != "你好" {
+		t.Errorf("output = %q, want %q", out, "你好")
+	}
+	if got := transport.requests.Load(); got != 1 {
+		t.Errorf("custom transport saw %d requests, want 1", got)
+	}
+	if len(paths) != 1 || !strings.HasSuffix(paths[0], "/chat/completions") {
+		t.Errorf("server paths = %v, want one chat completion request", paths)
+	}
+}
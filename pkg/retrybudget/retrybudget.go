@@ -0,0 +1,38 @@
+// Package retrybudget 在 context 中保存一次请求的重试预算，供翻译、agent 和 HTTP 传输等各层的重试点共享，
+// 避免各层重试相乘导致一次请求调用模型的次数失控。它不依赖项目中的其他包
+package retrybudget
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+)
+
+// budgetKey 是 context 中保存重试预算的键
+type budgetKey struct{}
+
+// budget 是一次请求中所有层共享的剩余重试次数
+type budget struct {
+	remaining atomic.Int64
+}
+
+// With 返回携带重试预算的 context：使用它发起的整个调用链总共最多重试 n 次。n < 0 按 0 处理
+func With(ctx context.Context, n int) context.Context {
+	b := &budget{}
+	b.remaining.Store(int64(max(n, 0)))
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// Spend 在重试前调用：从 ctx 的重试预算中扣除一次并返回是否允许重试。ctx 没有设置预算时总是允许
+func Spend(ctx context.Context) bool {
+	b, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return true
+	}
+	if b.remaining.Add(-1) < 0 {
+		b.remaining.Add(1)
+		log.Printf("Retry budget exhausted, not retrying")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package retrybudget
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestSpend(t *testing.T) {
+	// 没有设置预算时总是允许重试
+	if !Spend(context.Background()) {
+		t.Error("Spend() without a budget = false, want true")
+	}
+
+	ctx := With(context.Background(), 2)
+	for i := 0; i < 2; i++ {
+		if !Spend(ctx) {
+			t.Fatalf("Spend() #%d = false, want true", i+1)
+		}
+	}
+	if Spend(ctx) {
+		t.Error("Spend() after the budget is used up = true, want false")
+	}
+
+	if Spend(With(context.Background(), -1)) {
+		t.Error("Spend() with a negative budget = true, want false")
+	}
+}
+
+func TestSpend_Concurrent(t *testing.T) {
+	ctx := With(context.Background(), 10)
+	var (
+		mu      sync.Mutex
+		allowed int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if Spend(ctx) {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 10 {
+		t.Errorf("concurrent Spend() allowed %d retries, want 10", allowed)
+	}
+}
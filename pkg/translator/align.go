@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Segment 是一句原文及其译文
+type Segment struct {
+	Source string
+	Target string
+}
+
+// TranslateAligned 把原文按句切分后逐句翻译，返回原文和译文一一对应的句对，便于审校界面并排展示或单句修改。
+// 默认使用 SentenceSplitter，可通过 WithSplitter 更换；只含空白的片段会被忽略
+func TranslateAligned(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) ([]Segment, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyText
+	}
+
+	o := newOptions(opts)
+	splitter := o.splitter
+	if splitter == nil {
+		splitter = SentenceSplitter{}
+	}
+
+	var segments []Segment
+	for _, chunk := range splitter.Split(text) {
+		source := strings.TrimSpace(chunk)
+		if source == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		target, err := Translate(ctx, llm, source, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate segment %d: %w", len(segments)+1, err)
+		}
+		segments = append(segments, Segment{Source: source, Target: target})
+	}
+	return segments, nil
+}
@@ -0,0 +1,50 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTranslateAligned(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world.":      "你好，世界。",
+		"How are you?":      "你好吗？",
+		"See you tomorrow!": "明天见！",
+	})
+
+	text := "Hello world. How are you?\n\nSee you tomorrow!"
+	got, err := TranslateAligned(context.Background(), llm, text, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateAligned() error = %v", err)
+	}
+
+	sentences := SentenceSplitter{}.Split(text)
+	if len(got) != len(sentences) {
+		t.Fatalf("expected %d segments for %d source sentences, got %d", len(sentences), len(sentences), len(got))
+	}
+	want := []Segment{
+		{Source: "Hello world.", Target: "你好，世界。"},
+		{Source: "How are you?", Target: "你好吗？"},
+		{Source: "See you tomorrow!", Target: "明天见！"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateAligned() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTranslateAligned_Errors(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+
+	if _, err := TranslateAligned(ctx, newDictLLM(nil), "  ", "English", "Chinese"); !errors.Is(err, ErrEmptyText) {
+		t.Errorf("expected ErrEmptyText, got %v", err)
+	}
+
+	llm := newDictLLM(map[string]string{"Hello.": "你好。"})
+	if _, err := TranslateAligned(ctx, llm, "Hello. Unknown sentence.", "English", "Chinese"); err == nil {
+		t.Error("expected error when a segment fails to translate")
+	}
+}
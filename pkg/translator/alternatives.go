@@ -0,0 +1,76 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// 请求候选译文时的采样温度：第一轮使用 alternativeTemperature，之后每轮递增，最高到 maxAlternativeTemperature
+const (
+	alternativeTemperature    = 0.7
+	alternativeTemperatureInc = 0.1
+	maxAlternativeTemperature = 1.2
+)
+
+// TranslateAlternatives 返回最多 n 个互不相同的候选译文，供人工挑选。
+// 先在一次调用中请求 n 个候选（支持多候选的提供方会一次返回）；候选不足时以逐渐升高的温度
+// 再次请求，最多 2n 轮。相同的候选只保留第一个，模型始终给出相同译文时返回的候选可能少于 n 个。
+// 候选译文不写入缓存
+func TranslateAlternatives(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, n int, opts ...Option) ([]string, error) {
+	// 验证输入
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return nil, ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return nil, ErrEmptyOutputLanguage
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("number of alternatives must be positive, got %d", n)
+	}
+
+	o := newOptions(opts)
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"text":           text,
+	}
+	template := o.withHint(promptFor(inputLanguage, outputLanguage), values)
+
+	var candidates []string
+	seen := make(map[string]bool, n)
+	temperature := alternativeTemperature
+	for round := 0; round < 2*n && len(candidates) < n; round++ {
+		choices, err := runPromptChoices(ctx, llm, o, template, values,
+			llms.WithN(n-len(candidates)), llms.WithTemperature(temperature))
+		if err != nil {
+			return nil, fmt.Errorf("translation failed: %w", err)
+		}
+
+		for _, choice := range choices {
+			out, err := o.parse(choice)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(out) == "" || seen[out] {
+				continue
+			}
+			seen[out] = true
+			candidates = append(candidates, out)
+			if len(candidates) == n {
+				break
+			}
+		}
+		temperature = min(temperature+alternativeTemperatureInc, maxAlternativeTemperature)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("translation failed: %w", ErrEmptyResponse)
+	}
+	return candidates, nil
+}
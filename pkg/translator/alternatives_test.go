@@ -0,0 +1,96 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// multiChoiceLLM 每次调用依次返回 replies 中的一组候选，并记录调用选项
+type multiChoiceLLM struct {
+	replies [][]string
+	options []llms.CallOptions
+}
+
+func (m *multiChoiceLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, o := range options {
+		o(&opts)
+	}
+	m.options = append(m.options, opts)
+
+	reply := m.replies[0]
+	if len(m.replies) > 1 {
+		m.replies = m.replies[1:]
+	}
+	resp := &llms.ContentResponse{}
+	for _, content := range reply {
+		resp.Choices = append(resp.Choices, &llms.ContentChoice{Content: content})
+	}
+	return resp, nil
+}
+
+func (m *multiChoiceLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestTranslateAlternatives(t *testing.T) {
+	llm := &multiChoiceLLM{replies: [][]string{
+		{"你好", " 你好 ", "“您好”"},
+		{"嗨", "你好"},
+	}}
+
+	got, err := TranslateAlternatives(context.Background(), llm, "Hello", "English", "Chinese", 3)
+	if err != nil {
+		t.Fatalf("TranslateAlternatives() error = %v", err)
+	}
+	want := []string{"你好", "您好", "嗨"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("TranslateAlternatives() = %q, want %q", got, want)
+	}
+
+	// 第一轮请求全部候选，第二轮只请求缺少的数量，且温度升高
+	if len(llm.options) != 2 {
+		t.Fatalf("LLM called %d times, want 2", len(llm.options))
+	}
+	if llm.options[0].N != 3 || llm.options[1].N != 1 {
+		t.Errorf("requested N = %d, %d, want 3, 1", llm.options[0].N, llm.options[1].N)
+	}
+	if llm.options[1].Temperature <= llm.options[0].Temperature {
+		t.Errorf("temperature did not increase: %v -> %v", llm.options[0].Temperature, llm.options[1].Temperature)
+	}
+}
+
+func TestTranslateAlternatives_FewerDistinct(t *testing.T) {
+	// 不支持多候选且总是给出同一译文的模型：有限次重试后返回已有的候选
+	llm := &multiChoiceLLM{replies: [][]string{{"你好"}}}
+
+	got, err := TranslateAlternatives(context.Background(), llm, "Hello", "English", "Chinese", 2)
+	if err != nil {
+		t.Fatalf("TranslateAlternatives() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "你好" {
+		t.Errorf("TranslateAlternatives() = %q, want [你好]", got)
+	}
+	if len(llm.options) != 4 {
+		t.Errorf("LLM called %d times, want 4 (2n rounds)", len(llm.options))
+	}
+}
+
+func TestTranslateAlternatives_Errors(t *testing.T) {
+	ctx := context.Background()
+	llm := &multiChoiceLLM{replies: [][]string{{""}}}
+
+	if _, err := TranslateAlternatives(ctx, llm, "Hello", "English", "Chinese", 0); err == nil {
+		t.Error("expected error for n = 0")
+	}
+	if _, err := TranslateAlternatives(ctx, llm, "", "English", "Chinese", 2); !errors.Is(err, ErrEmptyText) {
+		t.Errorf("error = %v, want ErrEmptyText", err)
+	}
+	if _, err := TranslateAlternatives(ctx, llm, "Hello", "English", "Chinese", 1); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("error = %v, want ErrEmptyResponse", err)
+	}
+}
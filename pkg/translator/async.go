@@ -0,0 +1,89 @@
+package translator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateOutcome 是一次异步翻译的结果
+type TranslateOutcome struct {
+	Text string
+	Err  error
+}
+
+// TranslateAsync 在后台执行 Translate，立即返回一个通道。
+// 翻译完成后通道中恰好送达一个结果，随后通道被关闭；通道带缓冲，
+// 调用方不读取结果也不会导致后台 goroutine 泄漏
+func TranslateAsync(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) <-chan TranslateOutcome {
+	ch := make(chan TranslateOutcome, 1)
+	go func() {
+		defer close(ch)
+		result, err := Translate(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+		ch <- TranslateOutcome{Text: result, Err: err}
+	}()
+	return ch
+}
+
+// TranslateBatchStream 并发翻译 texts，每条完成后立即把结果（带 Index）送入返回的通道，
+// 全部完成后关闭通道。同时进行的翻译不超过 maxConcurrency 条，通道不带缓冲，
+// 结果不会在内存中累积，适合逐条处理海量文本。
+// 单条失败不会中断其他文本：按 FailureFallback 回退，或者在结果的 Err 中返回错误。
+// 调用方应读完通道；提前放弃时取消 ctx，未开始的文本不再翻译，后台 goroutine 随之退出
+func TranslateBatchStream(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) <-chan BatchResult {
+	out := make(chan BatchResult)
+	o := newOptions(opts)
+
+	indexes := make(chan int)
+	go func() {
+		defer close(indexes)
+		for i := range texts {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(maxConcurrency, len(texts)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := translateStreamItem(ctx, llm, texts[i], inputLanguage, outputLanguage, o, opts)
+				result.Index = i
+				select {
+				case out <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// translateStreamItem 翻译流式批量中的一条文本，错误放入结果而不是中断整个批量
+func translateStreamItem(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options, opts []Option) BatchResult {
+	if text == "" {
+		return BatchResult{Err: ErrEmptyText}
+	}
+	result, err := translateBatchItem(ctx, llm, text, inputLanguage, outputLanguage, o, opts)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+	if o.romanization && !result.Fallback && !usesLatinScript(outputLanguage) {
+		if result.Romanized, err = romanize(ctx, llm, result.Text, outputLanguage, o); err != nil {
+			return BatchResult{Text: result.Text, Err: err}
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTranslateAsync(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	ch := TranslateAsync(context.Background(), llm, "Hello", "English", "Chinese")
+
+	select {
+	case outcome, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed before delivering an outcome")
+		}
+		if outcome.Err != nil {
+			t.Fatalf("outcome error = %v", outcome.Err)
+		}
+		if outcome.Text != "你好" {
+			t.Errorf("outcome text = %q, want %q", outcome.Text, "你好")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for outcome")
+	}
+
+	// 只送达一个结果，随后通道关闭
+	select {
+	case outcome, ok := <-ch:
+		if ok {
+			t.Errorf("received a second outcome %+v", outcome)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after the outcome")
+	}
+}
+
+func TestTranslateAsync_Error(t *testing.T) {
+	outcome := <-TranslateAsync(context.Background(), newDictLLM(nil), "", "English", "Chinese")
+	if !errors.Is(outcome.Err, ErrEmptyText) {
+		t.Errorf("outcome error = %v, want ErrEmptyText", outcome.Err)
+	}
+	if outcome.Text != "" {
+		t.Errorf("outcome text = %q, want empty", outcome.Text)
+	}
+}
+
+func TestTranslateBatchStream(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) { return "译文", nil }}
+
+	texts := make([]string, 20)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("stream row %d", i)
+	}
+	texts[7] = "" // 单条失败不影响其他条目
+
+	seen := make(map[int]int)
+	for result := range TranslateBatchStream(context.Background(), llm, texts, "English", "Chinese") {
+		seen[result.Index]++
+		if result.Index == 7 {
+			if !errors.Is(result.Err, ErrEmptyText) {
+				t.Errorf("result[7].Err = %v, want ErrEmptyText", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || result.Text != "译文" {
+			t.Errorf("result[%d] = %+v, want 译文", result.Index, result)
+		}
+	}
+
+	for i := range texts {
+		if seen[i] != 1 {
+			t.Errorf("index %d emitted %d times, want exactly once", i, seen[i])
+		}
+	}
+	if len(seen) != len(texts) {
+		t.Errorf("emitted %d distinct indices, want %d", len(seen), len(texts))
+	}
+}
+
+func TestTranslateBatchStream_Cancel(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) { return "译文", nil }}
+
+	texts := make([]string, 100)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("cancel row %d", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := TranslateBatchStream(ctx, llm, texts, "English", "Chinese")
+	<-ch
+	cancel()
+
+	// 取消后通道最终关闭，不会发出全部结果
+	received := 1
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				if received == len(texts) {
+					t.Error("all results emitted despite cancellation")
+				}
+				return
+			}
+			received++
+		case <-timeout:
+			t.Fatal("channel not closed after cancellation")
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// numberedLinePattern 匹配编号列表中的一项，如 "1. 你好" 或 "2) 谢谢"
+var numberedLinePattern = regexp.MustCompile(`^\s*(\d+)[.)、:]\s*(.*)$`)
+
+// WithBatchBudget 设置 TranslateBatchAuto 每次调用的 token 预算（按 estimateTokens 估算），
+// n <= 0 时使用默认值
+func WithBatchBudget(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.batchBudget = n
+		}
+	}
+}
+
+// TranslateBatchAuto 按 token 预算把文本分组，每组通过 TranslateBatchCombined 合并为一次调用。
+// 每组尽量装满预算；单条文本不会被拆到两组，超出预算的单条文本独占一组
+func TranslateBatchAuto(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]string, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("empty texts input")
+	}
+
+	o := newOptions(opts)
+	results := make([]string, len(texts))
+	for _, group := range groupByBudget(texts, o.batchBudget) {
+		batch := make([]string, len(group))
+		for n, index := range group {
+			batch[n] = texts[index]
+		}
+		translated, err := TranslateBatchCombined(ctx, llm, batch, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate group starting at index %d: %w", group[0], err)
+		}
+		for n, index := range group {
+			results[index] = translated[n]
+		}
+	}
+	return results, nil
+}
+
+// groupByBudget 按顺序把文本下标分组，使每组估算的 token 数之和不超过 budget
+func groupByBudget(texts []string, budget int) [][]int {
+	var groups [][]int
+	var current []int
+	used := 0
+	for i, text := range texts {
+		size := estimateTokens(text)
+		if len(current) > 0 && used+size > budget {
+			groups = append(groups, current)
+			current, used = nil, 0
+		}
+		current = append(current, i)
+		used += size
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// estimateTokens 粗略估算文本的 token 数：ASCII 字符约 4 个一个 token，其他字符（如中日韩文字）各算一个
+func estimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	// 每条文本额外计入编号和换行的开销
+	return (ascii+3)/4 + other + 1
+}
+
+// TranslateBatchCombined 把多条文本编号后合并为一次 LLM 调用，再把模型返回的编号列表拆回逐条译文。
+// 适合大量短文本；返回的条数或编号与输入不符时退回逐条调用 Translate
+func TranslateBatchCombined(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]string, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("empty texts input")
+	}
+	if inputLanguage == "" {
+		return nil, ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return nil, ErrEmptyOutputLanguage
+	}
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("text at index %d: %w", i, ErrEmptyText)
+		}
+	}
+
+	o := newOptions(opts)
+
+	// 先查缓存并跳过已是目标语言的文本，只把剩下的交给模型
+	results := make([]string, len(texts))
+	hits := o.cacheGetMany(ctx, llm, texts, inputLanguage, outputLanguage)
+	var pending []int
+	for i, text := range texts {
+		if result, ok := hits[i]; ok {
+			results[i] = result
+			continue
+		}
+		if alreadyInLanguage(ctx, llm, text, outputLanguage, o) {
+			results[i] = text
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	var items strings.Builder
+	for n, index := range pending {
+		fmt.Fprintf(&items, "%d. %s\n", n+1, o.normalizeInput(texts[index]))
+	}
+
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"count":          len(pending),
+		"items":          items.String(),
+	}
+	out, err := runPrompt(ctx, llm, o, o.withHint(
+		`Translate each numbered item below from {{.inputLanguage}} to {{.outputLanguage}}.
+Return a numbered list with exactly {{.count}} items in the same order, formatted as "1. translation". Output the list only, no explanations.
+
+{{.items}}`, values), values)
+	if err != nil {
+		return nil, fmt.Errorf("batch translation failed: %w", err)
+	}
+
+	translations, ok := parseNumberedList(out, len(pending))
+	if !ok {
+		// 逐条重新翻译也是一次重试，从重试预算中扣除
+		if !SpendRetry(ctx) {
+			return nil, fmt.Errorf("batch translation failed: reply does not match %d items and the retry budget is exhausted", len(pending))
+		}
+		log.Printf("Combined batch reply does not match %d items, falling back to per-item translation", len(pending))
+		for _, index := range pending {
+			result, err := Translate(ctx, llm, texts[index], inputLanguage, outputLanguage, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to translate text at index %d: %w", index, err)
+			}
+			results[index] = result
+		}
+		return results, nil
+	}
+
+	entries := make(map[string]cacheEntry, len(pending))
+	for n, index := range pending {
+		result, err := o.parse(translations[n])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse translation at index %d: %w", index, err)
+		}
+		result = o.postEditText(result, inputLanguage, outputLanguage)
+		results[index] = result
+		key, entry := o.cacheEntry(ctx, o.cacheNormalization.apply(texts[index]), inputLanguage, outputLanguage, result)
+		entries[key] = entry
+	}
+	o.translationCache().setEntries(entries)
+	return results, nil
+}
+
+// parseNumberedList 把 "1. ...\n2. ..." 形式的回复解析为按顺序排列的条目。
+// 编号必须从 1 连续递增且总数等于 count；不带编号的行视为上一项的续行
+func parseNumberedList(reply string, count int) ([]string, bool) {
+	var items []string
+	for _, line := range strings.Split(reply, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		m := numberedLinePattern.FindStringSubmatch(line)
+		if m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n == len(items)+1 {
+				items = append(items, strings.TrimSpace(m[2]))
+				continue
+			}
+		}
+		if len(items) == 0 {
+			return nil, false
+		}
+		items[len(items)-1] += "\n" + strings.TrimSpace(line)
+	}
+	if len(items) != count {
+		return nil, false
+	}
+	for _, item := range items {
+		if item == "" {
+			return nil, false
+		}
+	}
+	return items, true
+}
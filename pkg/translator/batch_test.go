@@ -0,0 +1,207 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newCombinedLLM 创建一个 fakeLLM：合并批量的 prompt 返回 combined，其余 prompt 按词典逐条翻译
+func newCombinedLLM(combined string, dict map[string]string) *fakeLLM {
+	perItem := newDictLLM(dict)
+	return &fakeLLM{
+		respond: func(prompt string) (string, error) {
+			if strings.Contains(prompt, "numbered list") {
+				return combined, nil
+			}
+			return perItem.respond(prompt)
+		},
+	}
+}
+
+func TestTranslateBatchCombined(t *testing.T) {
+	defaultCache.Clear()
+	llm := newCombinedLLM("1. 你好\n2. 谢谢\n3. 再见", nil)
+
+	got, err := TranslateBatchCombined(context.Background(), llm, []string{"Hello", "Thank you", "Goodbye"}, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchCombined() error = %v", err)
+	}
+	want := []string{"你好", "谢谢", "再见"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatchCombined() = %q, want %q", got, want)
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("expected 1 combined LLM call, got %d", llm.Calls())
+	}
+	for _, want := range []string{"1. Hello", "2. Thank you", "3. Goodbye"} {
+		if !strings.Contains(llm.prompts[0], want) {
+			t.Errorf("combined prompt missing %q: %s", want, llm.prompts[0])
+		}
+	}
+	if cached, ok := defaultCache.Get("Thank you", "English", "Chinese"); !ok || cached != "谢谢" {
+		t.Errorf("expected split result to be cached, got %q, %v", cached, ok)
+	}
+}
+
+func TestTranslateBatchCombined_CachedItemsSkipped(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+	llm := newCombinedLLM("1. 谢谢", nil)
+
+	got, err := TranslateBatchCombined(context.Background(), llm, []string{"Hello", "Thank you"}, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchCombined() error = %v", err)
+	}
+	if want := []string{"你好", "谢谢"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatchCombined() = %q, want %q", got, want)
+	}
+	if strings.Contains(llm.prompts[0], "Hello") {
+		t.Errorf("cached text should not be sent to the model: %s", llm.prompts[0])
+	}
+}
+
+func TestTranslateBatchCombined_Fallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{name: "Count Mismatch", reply: "1. 你好\n2. 谢谢"},
+		{name: "No Numbering", reply: "你好，谢谢，再见"},
+		{name: "Out Of Order", reply: "1. 你好\n3. 再见\n2. 谢谢"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			llm := newCombinedLLM(tt.reply, map[string]string{
+				"Hello":     "你好",
+				"Thank you": "谢谢",
+				"Goodbye":   "再见",
+			})
+
+			got, err := TranslateBatchCombined(context.Background(), llm, []string{"Hello", "Thank you", "Goodbye"}, "English", "Chinese")
+			if err != nil {
+				t.Fatalf("TranslateBatchCombined() error = %v", err)
+			}
+			if want := []string{"你好", "谢谢", "再见"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("TranslateBatchCombined() = %q, want %q", got, want)
+			}
+			// 1 次合并调用 + 3 次逐条调用
+			if llm.Calls() != 4 {
+				t.Errorf("expected 4 LLM calls with fallback, got %d", llm.Calls())
+			}
+		})
+	}
+}
+
+func TestTranslateBatchCombined_Errors(t *testing.T) {
+	llm := newCombinedLLM("1. 你好", nil)
+	ctx := context.Background()
+
+	if _, err := TranslateBatchCombined(ctx, llm, nil, "English", "Chinese"); err == nil {
+		t.Error("expected error for empty texts")
+	}
+	if _, err := TranslateBatchCombined(ctx, llm, []string{"Hello", " "}, "English", "Chinese"); !errors.Is(err, ErrEmptyText) {
+		t.Errorf("expected ErrEmptyText, got %v", err)
+	}
+	if _, err := TranslateBatchCombined(ctx, llm, []string{"Hello"}, "", "Chinese"); !errors.Is(err, ErrEmptyInputLanguage) {
+		t.Errorf("expected ErrEmptyInputLanguage, got %v", err)
+	}
+}
+
+func TestParseNumberedList(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  string
+		count  int
+		want   []string
+		wantOK bool
+	}{
+		{name: "Dot Numbering", reply: "1. a\n2. b", count: 2, want: []string{"a", "b"}, wantOK: true},
+		{name: "Paren Numbering And Blank Lines", reply: "1) a\n\n2) b\n", count: 2, want: []string{"a", "b"}, wantOK: true},
+		{name: "Continuation Line", reply: "1. a\nmore\n2. b", count: 2, want: []string{"a\nmore", "b"}, wantOK: true},
+		{name: "Preamble", reply: "Here you go:\n1. a\n2. b", count: 2, wantOK: false},
+		{name: "Too Few", reply: "1. a", count: 2, wantOK: false},
+		{name: "Empty Item", reply: "1. a\n2.", count: 2, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseNumberedList(tt.reply, tt.count)
+			if ok != tt.wantOK {
+				t.Fatalf("parseNumberedList() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNumberedList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupByBudget(t *testing.T) {
+	short := "a"                      // 2 tokens
+	medium := strings.Repeat("m", 36) // 10 tokens
+	long := strings.Repeat("l", 80)   // 21 tokens，超出预算
+	chinese := strings.Repeat("中", 5) // 6 tokens
+
+	tests := []struct {
+		name   string
+		texts  []string
+		budget int
+		want   [][]int
+	}{
+		{name: "All Fit", texts: []string{short, short, short}, budget: 10, want: [][]int{{0, 1, 2}}},
+		{name: "Exact Budget", texts: []string{short, short, chinese, short}, budget: 10, want: [][]int{{0, 1, 2}, {3}}},
+		{name: "Medium Fills Group", texts: []string{short, short, medium, short}, budget: 10, want: [][]int{{0, 1}, {2}, {3}}},
+		{name: "Oversized Alone", texts: []string{short, long, short, short}, budget: 10, want: [][]int{{0}, {1}, {2, 3}}},
+		{name: "Only Oversized", texts: []string{long, long}, budget: 10, want: [][]int{{0}, {1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupByBudget(tt.texts, tt.budget); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupByBudget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateBatchAuto(t *testing.T) {
+	defaultCache.Clear()
+	dict := map[string]string{
+		"Hello":                    "你好",
+		"Thank you":                "谢谢",
+		strings.Repeat("long ", 8): "很长的句子",
+		"Goodbye":                  "再见",
+	}
+	// 按编号原样回复 prompt 中每一项的译文
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		var reply strings.Builder
+		for _, line := range strings.Split(prompt, "\n") {
+			m := numberedLinePattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if tr, ok := dict[m[2]]; ok {
+				reply.WriteString(m[1] + ". " + tr + "\n")
+			}
+		}
+		return reply.String(), nil
+	}}
+
+	texts := []string{"Hello", "Thank you", strings.Repeat("long ", 8), "Goodbye"}
+	got, err := TranslateBatchAuto(context.Background(), llm, texts, "English", "Chinese", WithBatchBudget(8))
+	if err != nil {
+		t.Fatalf("TranslateBatchAuto() error = %v", err)
+	}
+	if want := []string{"你好", "谢谢", "很长的句子", "再见"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatchAuto() = %q, want %q", got, want)
+	}
+	// "Hello" 和 "Thank you" 共 7 个 token 合为一组，长句和 "Goodbye" 各占一组
+	if llm.Calls() != 3 {
+		t.Errorf("expected 3 grouped LLM calls, got %d", llm.Calls())
+	}
+}
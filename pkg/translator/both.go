@@ -0,0 +1,97 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// bothCacheTag 用于区分直译/意译组合结果和普通译文的缓存键
+const bothCacheTag = "both"
+
+// bothPrompt 要求模型以 JSON 同时返回直译和意译
+const bothPrompt = `Translate the following text from {{.inputLanguage}} to {{.outputLanguage}} twice:
+- "literal": a faithful, word-for-word rendering that keeps the original structure as far as {{.outputLanguage}} grammar allows;
+- "idiomatic": a natural rendering a native {{.outputLanguage}} speaker would write.
+Reply with a JSON object only, in the form {"literal": "...", "idiomatic": "..."}.
+Text: {{.text}}`
+
+// bothReply 是 bothPrompt 期望的回复格式，也是组合结果在缓存中的存储格式
+type bothReply struct {
+	Literal   string `json:"literal"`
+	Idiomatic string `json:"idiomatic"`
+}
+
+// TranslateBoth 一次调用同时得到直译和意译，适合需要对照措辞的内容。
+// 两个结果作为一条缓存一起读写，与 Translate 的缓存互不影响；模型回复无法解析时返回错误且不缓存
+func TranslateBoth(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (literal string, idiomatic string, err error) {
+	// 验证输入
+	if text == "" {
+		return "", "", ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return "", "", ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return "", "", ErrEmptyOutputLanguage
+	}
+
+	o := newOptions(opts)
+	ctx, cancel := o.withDeadline(ctx)
+	defer cancel()
+
+	// 检查缓存
+	key := hashKeyParts(o.cacheKey(ctx, o.cacheNormalization.apply(text), inputLanguage, outputLanguage), bothCacheTag)
+	if cached, ok := o.translationCache().getKey(key); ok {
+		var reply bothReply
+		if err := json.Unmarshal([]byte(cached), &reply); err == nil {
+			return reply.Literal, reply.Idiomatic, nil
+		}
+	}
+
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"text":           text,
+	}
+	out, err := runPrompt(ctx, llm, o, o.withHint(bothPrompt, values), values)
+	if err != nil {
+		return "", "", fmt.Errorf("translation failed: %w", err)
+	}
+
+	reply, err := parseBothReply(out)
+	if err != nil {
+		return "", "", err
+	}
+	for _, field := range []*string{&reply.Literal, &reply.Idiomatic} {
+		if *field, err = o.parse(*field); err != nil {
+			return "", "", err
+		}
+		*field = o.postEditText(*field, inputLanguage, outputLanguage)
+	}
+
+	encoded, err := json.Marshal(reply)
+	if err == nil {
+		o.translationCache().setKey(key, string(encoded))
+	}
+	return reply.Literal, reply.Idiomatic, nil
+}
+
+// parseBothReply 解析模型返回的 JSON，容忍代码块包裹和对象前后的多余文字，两个译文都不能为空
+func parseBothReply(out string) (bothReply, error) {
+	var reply bothReply
+	object, ok := jsonObject(out)
+	if !ok {
+		return reply, fmt.Errorf("malformed literal/idiomatic reply, no JSON object found: %q", out)
+	}
+	if err := json.Unmarshal([]byte(object), &reply); err != nil {
+		return reply, fmt.Errorf("malformed literal/idiomatic reply %q: %w", out, err)
+	}
+	if strings.TrimSpace(reply.Literal) == "" || strings.TrimSpace(reply.Idiomatic) == "" {
+		return reply, fmt.Errorf("malformed literal/idiomatic reply, missing a translation: %q", out)
+	}
+	return reply, nil
+}
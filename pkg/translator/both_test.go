@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateBoth(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Here you go:\n```json\n{\"literal\": \"下着猫和狗的雨\", \"idiomatic\": \"倾盆大雨\"}\n```", nil
+	}}
+	ctx := context.Background()
+
+	literal, idiomatic, err := TranslateBoth(ctx, llm, "It's raining cats and dogs", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBoth() error = %v", err)
+	}
+	if literal != "下着猫和狗的雨" || idiomatic != "倾盆大雨" {
+		t.Errorf("TranslateBoth() = %q, %q", literal, idiomatic)
+	}
+
+	// 两个结果一起缓存，且不影响普通译文的缓存
+	literal, idiomatic, err = TranslateBoth(ctx, llm, "It's raining cats and dogs", "English", "Chinese")
+	if err != nil || literal != "下着猫和狗的雨" || idiomatic != "倾盆大雨" {
+		t.Errorf("cached TranslateBoth() = %q, %q, %v", literal, idiomatic, err)
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times, want 1", llm.Calls())
+	}
+	if _, ok := defaultCache.Get("It's raining cats and dogs", "English", "Chinese"); ok {
+		t.Error("TranslateBoth should not populate the plain translation cache")
+	}
+}
+
+func TestTranslateBoth_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{name: "Not JSON", reply: "倾盆大雨"},
+		{name: "Invalid JSON", reply: `{"literal": "下雨"`},
+		{name: "Missing Idiomatic", reply: `{"literal": "下着猫和狗的雨"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			llm := &fakeLLM{respond: func(prompt string) (string, error) { return tt.reply, nil }}
+
+			_, _, err := TranslateBoth(context.Background(), llm, "It's raining", "English", "Chinese")
+			if err == nil || !strings.Contains(err.Error(), "malformed literal/idiomatic reply") {
+				t.Errorf("error = %v, want malformed reply error", err)
+			}
+			if cacheLen(defaultCache) != 0 {
+				t.Error("malformed reply should not be cached")
+			}
+		})
+	}
+}
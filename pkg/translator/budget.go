@@ -0,0 +1,21 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/costa92/langchaingo-demo/pkg/retrybudget"
+)
+
+// WithRetryBudget 返回携带重试预算的 context：使用它发起的整个调用链（Translate 内部的空回复、回显、
+// 可疑输出、质量和回译重试，合并批量翻译的逐条回退，翻译工具，agent 的重试，以及 provider 的 HTTP 重试）总共最多重试 n 次，避免各层重试相乘导致
+// 一次请求调用模型的次数失控。预算用完后各处按重试次数耗尽处理。n < 0 按 0 处理。
+// 预算由 retrybudget 包保存，与 retrybudget.With 等价
+func WithRetryBudget(ctx context.Context, n int) context.Context {
+	return retrybudget.With(ctx, n)
+}
+
+// SpendRetry 在重试前调用：从 ctx 的重试预算中扣除一次并返回是否允许重试。
+// ctx 没有设置预算时总是允许，供其他包的重试点（如 agent）共享同一个预算，与 retrybudget.Spend 等价
+func SpendRetry(ctx context.Context) bool {
+	return retrybudget.Spend(ctx)
+}
@@ -0,0 +1,106 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithRetryBudget(t *testing.T) {
+	// 第一次返回空内容，之后始终回显原文：空回复重试和回显重试嵌套在同一次请求中
+	newLLM := func() *fakeLLM {
+		llm := &fakeLLM{}
+		llm.respond = func(string) (string, error) {
+			if llm.Calls() == 1 {
+				return "", nil
+			}
+			return "Good morning", nil
+		}
+		return llm
+	}
+	opts := []Option{WithEchoGuard(5), WithNoCache()}
+
+	// 没有预算时：1 次请求 + 1 次空回复重试 + 5 次回显重试
+	llm := newLLM()
+	if _, err := Translate(context.Background(), llm, "Good morning", "English", "Chinese", opts...); !errors.Is(err, ErrEcho) {
+		t.Fatalf("Translate() error = %v, want ErrEcho", err)
+	}
+	if llm.Calls() != 7 {
+		t.Fatalf("LLM called %d times without a budget, want 7", llm.Calls())
+	}
+
+	// 预算为 2 时，两次请求共享预算：第一次请求的空回复重试和一次回显重试用完预算，
+	// 第二次请求不再重试，总调用次数恰好是请求数加预算
+	const budget = 2
+	llm = newLLM()
+	ctx := WithRetryBudget(context.Background(), budget)
+	for i := 0; i < 2; i++ {
+		if _, err := Translate(ctx, llm, "Good morning", "English", "Chinese", opts...); !errors.Is(err, ErrEcho) {
+			t.Fatalf("Translate() error = %v, want ErrEcho", err)
+		}
+	}
+	if llm.Calls() != 2+budget {
+		t.Errorf("LLM called %d times, want %d", llm.Calls(), 2+budget)
+	}
+	if SpendRetry(ctx) {
+		t.Error("SpendRetry() after both requests = true, want the budget to be used up")
+	}
+}
+
+func TestWithRetryBudget_CombinedBatchFallback(t *testing.T) {
+	// 合并回复的条数不符，需要逐条回退翻译
+	newLLM := func() *fakeLLM {
+		return &fakeLLM{respond: func(prompt string) (string, error) {
+			if strings.Contains(prompt, "numbered list") {
+				return "1. 你好", nil
+			}
+			return newDictLLM(map[string]string{"Hello": "你好", "Thank you": "谢谢"}).respond(prompt)
+		}}
+	}
+	texts := []string{"Hello", "Thank you"}
+
+	// 预算用完时不回退，只有合并调用一次
+	llm := newLLM()
+	ctx := WithRetryBudget(context.Background(), 0)
+	if _, err := TranslateBatchCombined(ctx, llm, texts, "English", "Chinese", WithNoCache()); err == nil {
+		t.Error("TranslateBatchCombined() error = nil, want an error when the budget is exhausted")
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times with an exhausted budget, want 1", llm.Calls())
+	}
+
+	// 回退扣除一次预算，之后逐条翻译
+	llm = newLLM()
+	ctx = WithRetryBudget(context.Background(), 1)
+	got, err := TranslateBatchCombined(ctx, llm, texts, "English", "Chinese", WithNoCache())
+	if err != nil {
+		t.Fatalf("TranslateBatchCombined() error = %v", err)
+	}
+	if want := []string{"你好", "谢谢"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatchCombined() = %q, want %q", got, want)
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("LLM called %d times, want 3 (combined call and 2 per-item calls)", llm.Calls())
+	}
+	if SpendRetry(ctx) {
+		t.Error("SpendRetry() after the fallback = true, want the budget to be used up")
+	}
+}
+
+func TestSpendRetry(t *testing.T) {
+	if !SpendRetry(context.Background()) {
+		t.Error("SpendRetry() without a budget = false, want true")
+	}
+
+	ctx := WithRetryBudget(context.Background(), 1)
+	if !SpendRetry(ctx) {
+		t.Error("first SpendRetry() = false, want true")
+	}
+	for i := 0; i < 2; i++ {
+		if SpendRetry(ctx) {
+			t.Error("SpendRetry() after the budget is used = true, want false")
+		}
+	}
+}
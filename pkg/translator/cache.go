@@ -0,0 +1,573 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TranslationCache 用于缓存翻译结果
+type TranslationCache struct {
+	cache map[string]cacheEntry
+	mu    sync.RWMutex
+
+	ttl           time.Duration    // 缓存有效期
+	sweepInterval time.Duration    // 后台清理间隔，0 表示不启动后台清理
+	clock         func() time.Time // 时间来源，默认 time.Now，可通过 WithClock 替换
+
+	// 最近写入的带原文条目的键，环形缓冲，供模糊匹配有限地扫描
+	recent     []string
+	recentNext int
+
+	// 为 true 时读写都是空操作，见 DisableCache
+	disabled atomic.Bool
+
+	// 过期条目是否先作为旧值返回、再在后台刷新，见 WithStaleWhileRevalidate
+	staleWhileRevalidate bool
+	refreshing           map[string]struct{} // 正在后台刷新的缓存键，受 mu 保护
+
+	// 后台任务的生命周期管理
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+type cacheEntry struct {
+	result    string
+	timestamp time.Time
+
+	// 原文和语言对，仅通过 Set 写入的条目才有，用于导出翻译记忆
+	source     string
+	inputLang  string
+	outputLang string
+}
+
+var (
+	defaultCache = NewTranslationCache()
+
+	// uncached 是始终禁用的缓存，WithNoCache 的调用通过它读写，不会保存任何内容
+	uncached = newDisabledCache()
+)
+
+// newDisabledCache 创建一个读写均为空操作的缓存
+func newDisabledCache() *TranslationCache {
+	c := NewTranslationCache()
+	c.disabled.Store(true)
+	return c
+}
+
+// DisableCache 全局关闭共享的内存缓存（包括译文和语言检测结果）并清空已有条目。
+// 关闭后缓存的读取始终未命中、写入不保存任何内容，每次翻译都会调用模型，适合不允许在内存中保留文本的部署环境
+func DisableCache() {
+	for _, c := range []*TranslationCache{defaultCache, detectCache} {
+		c.disabled.Store(true)
+		c.Clear()
+	}
+}
+
+// EnableCache 重新开启被 DisableCache 关闭的共享缓存
+func EnableCache() {
+	for _, c := range []*TranslationCache{defaultCache, detectCache} {
+		c.disabled.Store(false)
+	}
+}
+
+// WithNoCache 让本次调用既不读取也不写入共享缓存，其他调用不受影响
+func WithNoCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+// translationCache 返回本次调用读写译文使用的缓存
+func (o *options) translationCache() *TranslationCache {
+	if o.noCache {
+		return uncached
+	}
+	return defaultCache
+}
+
+// Cached 只查询共享缓存中 text 的译文，不做输入校验、不构建配置，也不会调用模型。
+// 适合在决定是否排队翻译前快速判断；只能查到不带提示、命名空间、模型和缓存键规范化的 Translate 写入的结果。
+// text 与 Translate 一样先规范化为 NFC
+func Cached(text, inputLang, outputLang string) (string, bool) {
+	return defaultCache.getKey(getCacheKey(norm.NFC.String(text), inputLang, outputLang))
+}
+
+// CacheOption 用于配置 TranslationCache
+type CacheOption func(*TranslationCache)
+
+// WithTTL 设置缓存条目的有效期，默认 24 小时
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *TranslationCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithSweepInterval 启动后台清理任务，每隔 d 删除一次过期条目，需要调用 Close 停止。
+// 默认不启动，过期条目只在读取时被惰性清理。开启 WithStaleWhileRevalidate 时过期条目要留给读取方作为旧值返回，
+// 后台清理不删除任何条目
+func WithSweepInterval(d time.Duration) CacheOption {
+	return func(c *TranslationCache) {
+		c.sweepInterval = d
+	}
+}
+
+// WithClock 替换缓存读取当前时间的方式，默认使用 time.Now。
+// 测试中可以传入手动推进的时钟，无需真实等待即可验证过期行为
+func WithClock(clock func() time.Time) CacheOption {
+	return func(c *TranslationCache) {
+		c.clock = clock
+	}
+}
+
+// NewTranslationCache 创建一个新的翻译缓存
+func NewTranslationCache(opts ...CacheOption) *TranslationCache {
+	c := &TranslationCache{
+		cache:      make(map[string]cacheEntry),
+		ttl:        cacheDuration,
+		clock:      time.Now,
+		stop:       make(chan struct{}),
+		refreshing: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.sweepInterval > 0 {
+		c.goBackground(c.sweepLoop)
+	}
+	return c
+}
+
+// sweepLoop 定期清理过期条目，直到 stop 被关闭
+func (c *TranslationCache) sweepLoop(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.sweepExpired()
+		}
+	}
+}
+
+// sweepExpired 删除所有过期条目，返回删除的数量。开启 stale-while-revalidate 时不删除，过期条目由后台刷新替换
+func (c *TranslationCache) sweepExpired() int {
+	if c.staleWhileRevalidate {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock()
+	removed := 0
+	for key, entry := range c.cache {
+		if now.Sub(entry.timestamp) >= c.ttl {
+			delete(c.cache, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// goBackground 启动一个受 Close 管理的后台任务，stop 关闭时任务应尽快返回
+func (c *TranslationCache) goBackground(fn func(stop <-chan struct{})) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		fn(c.stop)
+	}()
+}
+
+// Close 停止缓存的所有后台任务并等待其退出。
+// Close 可以重复调用；关闭后缓存仍可读写，但不再有后台任务运行。
+func (c *TranslationCache) Close() error {
+	c.closeOnce.Do(func() {
+		// 持有 mu 关闭，与 startRefresh 的登记互斥
+		c.mu.Lock()
+		if c.stop != nil {
+			close(c.stop)
+		}
+		c.mu.Unlock()
+	})
+	c.wg.Wait()
+	return nil
+}
+
+// CacheNormalization 指定计算缓存键前如何规范化原文，让仅有细微差别的请求共享缓存。
+// 只影响缓存键，发给模型的仍是原始文本
+type CacheNormalization struct {
+	Trim               bool // 去掉首尾空白
+	Lowercase          bool // 转为小写；对大小写敏感的内容（如代码、专有名词）不安全，需显式开启
+	CollapseWhitespace bool // 把连续空白合并为一个空格
+}
+
+// WithCacheNormalization 设置计算缓存键时对原文的规范化方式
+func WithCacheNormalization(n CacheNormalization) Option {
+	return func(o *options) {
+		o.cacheNormalization = n
+	}
+}
+
+// apply 按配置规范化用于缓存键的文本
+func (n CacheNormalization) apply(text string) string {
+	if n.CollapseWhitespace {
+		text = strings.Join(strings.Fields(text), " ")
+	} else if n.Trim {
+		text = strings.TrimSpace(text)
+	}
+	if n.Lowercase {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// getCacheKey 生成缓存键。BCP-47 标签形式的语言按规范大小写参与计算，
+// zh-TW 与 zh-CN 是不同的条目，zh_tw 与 zh-TW 是同一个条目
+func getCacheKey(text, inputLang, outputLang string) string {
+	return hashKeyParts(text, canonicalLocale(inputLang), canonicalLocale(outputLang))
+}
+
+// hashKeyParts 对带长度前缀的各字段做 SHA-256，得到固定长度的键。
+// 长度前缀保证字段内容中的分隔符不会造成跨字段冲突。
+func hashKeyParts(parts ...string) string {
+	h := sha256.New()
+	var lenBuf [8]byte
+	for _, p := range parts {
+		binary.BigEndian.PutUint64(lenBuf[:], uint64(len(p)))
+		h.Write(lenBuf[:])
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// namespaceCacheTag 用于区分带命名空间和不带命名空间的缓存键
+const namespaceCacheTag = "namespace"
+
+// WithCacheNamespace 把命名空间（如 "v2" 或 "prompt-2024-06"）计入缓存键。
+// 修改提示词模板或模型后换一个命名空间，旧的缓存条目便不再命中，无需清空缓存；
+// 不同命名空间的条目互相独立，过期后正常清理
+func WithCacheNamespace(ns string) Option {
+	return func(o *options) {
+		o.cacheNamespace = ns
+	}
+}
+
+// modelCacheTag 用于区分指定了模型和使用默认模型的缓存键
+const modelCacheTag = "model"
+
+// plainCacheKey 判断缓存键是否只由原文和语言对组成，与 Get/Set 使用的键相同
+func (o *options) plainCacheKey(ctx context.Context) bool {
+	model, _ := o.modelName(ctx)
+	return o.hint == "" && o.cacheNamespace == "" && model == ""
+}
+
+// cacheKey 计算翻译结果的缓存键。原文先按配置做 Unicode 规范化，所有读写缓存的路径因此使用同一种写法；
+// 设置了提示、命名空间或模型（WithModel 或 context 中的覆盖值）时它们参与计算
+func (o *options) cacheKey(ctx context.Context, cacheText, inputLang, outputLang string) string {
+	cacheText = o.normalizeInput(cacheText)
+	if o.plainCacheKey(ctx) {
+		return getCacheKey(cacheText, inputLang, outputLang)
+	}
+	parts := []string{cacheText, canonicalLocale(inputLang), canonicalLocale(outputLang)}
+	if o.hint != "" {
+		parts = append(parts, hintCacheTag, o.hint)
+	}
+	if o.cacheNamespace != "" {
+		parts = append(parts, namespaceCacheTag, o.cacheNamespace)
+	}
+	if model, ok := o.modelName(ctx); ok {
+		parts = append(parts, modelCacheTag, model)
+	}
+	return hashKeyParts(parts...)
+}
+
+// cacheSet 按 cacheKey 写入翻译结果
+func (o *options) cacheSet(ctx context.Context, cacheText, inputLang, outputLang, result string) {
+	o.translationCache().setEntry(o.cacheEntry(ctx, cacheText, inputLang, outputLang, result))
+}
+
+// cacheEntry 返回翻译结果的缓存键和条目；不带提示、命名空间和模型的结果同时记录原文，供导出翻译记忆
+func (o *options) cacheEntry(ctx context.Context, cacheText, inputLang, outputLang, result string) (string, cacheEntry) {
+	cacheText = o.normalizeInput(cacheText)
+	if !o.plainCacheKey(ctx) {
+		return o.cacheKey(ctx, cacheText, inputLang, outputLang), cacheEntry{result: result}
+	}
+	return getCacheKey(cacheText, inputLang, outputLang), cacheEntry{
+		result:     result,
+		source:     cacheText,
+		inputLang:  inputLang,
+		outputLang: outputLang,
+	}
+}
+
+// cacheGetMany 一次性查找多段文本的缓存结果，返回命中条目的下标到译文的映射。
+// 开启 stale-while-revalidate 时过期条目也算命中，并与 Translate 一样用 llm 在后台刷新
+func (o *options) cacheGetMany(ctx context.Context, llm llms.Model, texts []string, inputLang, outputLang string) map[int]string {
+	sources := make([]string, len(texts))
+	cacheTexts := make([]string, len(texts))
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		sources[i] = o.normalizeInput(text)
+		cacheTexts[i] = o.cacheNormalization.apply(sources[i])
+		keys[i] = o.cacheKey(ctx, cacheTexts[i], inputLang, outputLang)
+	}
+	found, stale := o.translationCache().getKeys(keys)
+
+	hits := make(map[int]string, len(found))
+	for i, key := range keys {
+		result, ok := found[key]
+		if !ok {
+			continue
+		}
+		hits[i] = result
+		if stale[key] {
+			o.revalidate(ctx, llm, sources[i], cacheTexts[i], inputLang, outputLang, key)
+		}
+	}
+	return hits
+}
+
+// Get 从缓存获取翻译结果。开启 WithStaleWhileRevalidate 时也返回已过期的条目，需要区分时使用 Lookup
+func (c *TranslationCache) Get(text, inputLang, outputLang string) (string, bool) {
+	result, _, ok := c.lookupKey(getCacheKey(text, inputLang, outputLang))
+	return result, ok
+}
+
+// Set 设置缓存
+func (c *TranslationCache) Set(text, inputLang, outputLang, result string) {
+	c.setEntry(getCacheKey(text, inputLang, outputLang), cacheEntry{
+		result:     result,
+		source:     text,
+		inputLang:  inputLang,
+		outputLang: outputLang,
+	})
+}
+
+// getKey 按已计算好的缓存键读取未过期的条目
+func (c *TranslationCache) getKey(key string) (string, bool) {
+	result, stale, ok := c.lookupKey(key)
+	return result, ok && !stale
+}
+
+// lookupKey 按已计算好的缓存键读取，stale 表示条目已过期。
+// 开启 stale-while-revalidate 时过期条目保留并返回，否则被清理并视为未命中
+func (c *TranslationCache) lookupKey(key string) (result string, stale bool, ok bool) {
+	if c.disabled.Load() {
+		return "", false, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.cache[key]
+	c.mu.RUnlock()
+	if !ok {
+		return "", false, false
+	}
+	if c.clock().Sub(entry.timestamp) < c.ttl {
+		return entry.result, false, true
+	}
+	if c.staleWhileRevalidate {
+		return entry.result, true, true
+	}
+
+	// 清理过期缓存：删除需要写锁，并确认条目未被并发更新
+	c.mu.Lock()
+	if current, ok := c.cache[key]; ok && current.timestamp.Equal(entry.timestamp) {
+		delete(c.cache, key)
+	}
+	c.mu.Unlock()
+	return "", false, false
+}
+
+// setKey 按已计算好的缓存键写入
+func (c *TranslationCache) setKey(key, result string) {
+	c.setEntry(key, cacheEntry{result: result})
+}
+
+// setEntry 写入条目并记录写入时间
+func (c *TranslationCache) setEntry(key string, entry cacheEntry) {
+	c.setEntries(map[string]cacheEntry{key: entry})
+}
+
+// CacheKey 标识一条缓存：原文和语言对
+type CacheKey struct {
+	Text       string
+	InputLang  string
+	OutputLang string
+}
+
+// GetMany 批量读取缓存，只加一次读锁；返回值只包含命中的条目
+func (c *TranslationCache) GetMany(keys []CacheKey) map[CacheKey]string {
+	hashed := make([]string, len(keys))
+	for i, k := range keys {
+		hashed[i] = getCacheKey(k.Text, k.InputLang, k.OutputLang)
+	}
+	found, _ := c.getKeys(hashed)
+
+	results := make(map[CacheKey]string, len(found))
+	for i, k := range keys {
+		if result, ok := found[hashed[i]]; ok {
+			results[k] = result
+		}
+	}
+	return results
+}
+
+// SetMany 批量写入缓存，只加一次写锁，效果与逐条调用 Set 相同
+func (c *TranslationCache) SetMany(entries map[CacheKey]string) {
+	hashed := make(map[string]cacheEntry, len(entries))
+	for k, result := range entries {
+		hashed[getCacheKey(k.Text, k.InputLang, k.OutputLang)] = cacheEntry{
+			result:     result,
+			source:     k.Text,
+			inputLang:  k.InputLang,
+			outputLang: k.OutputLang,
+		}
+	}
+	c.setEntries(hashed)
+}
+
+// Preload 直接写入已知的译文，所有条目在一次加锁内以当前时间写入，效果与 SetMany 相同。
+// 用于测试夹具和数据初始化，写入后 Translate 对这些文本和语言对直接命中缓存，不会调用模型
+func (c *TranslationCache) Preload(entries map[CacheKey]string) {
+	c.SetMany(entries)
+}
+
+// getKeys 按已计算好的缓存键批量读取，过期条目与 getKey 一样被清理。
+// 开启 stale-while-revalidate 时过期条目与 lookupKey 一样照常返回，并在 stale 中标记
+func (c *TranslationCache) getKeys(keys []string) (results map[string]string, stale map[string]bool) {
+	results = make(map[string]string, len(keys))
+	stale = make(map[string]bool)
+	if c.disabled.Load() {
+		return results, stale
+	}
+
+	expired := make(map[string]time.Time)
+
+	c.mu.RLock()
+	now := c.clock()
+	for _, key := range keys {
+		entry, ok := c.cache[key]
+		if !ok {
+			continue
+		}
+		switch {
+		case now.Sub(entry.timestamp) < c.ttl:
+			results[key] = entry.result
+		case c.staleWhileRevalidate:
+			results[key] = entry.result
+			stale[key] = true
+		default:
+			expired[key] = entry.timestamp
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(expired) > 0 {
+		c.mu.Lock()
+		for key, timestamp := range expired {
+			if current, ok := c.cache[key]; ok && current.timestamp.Equal(timestamp) {
+				delete(c.cache, key)
+			}
+		}
+		c.mu.Unlock()
+	}
+	return results, stale
+}
+
+// setEntries 批量写入条目，所有条目使用同一个写入时间
+func (c *TranslationCache) setEntries(entries map[string]cacheEntry) {
+	if c.disabled.Load() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock()
+	for key, entry := range entries {
+		entry.timestamp = now
+		c.cache[key] = entry
+		if entry.source != "" {
+			c.recordRecent(key)
+		}
+	}
+}
+
+// Delete 删除指定文本和语言对的缓存条目
+func (c *TranslationCache) Delete(text, inputLang, outputLang string) {
+	c.deleteKey(getCacheKey(text, inputLang, outputLang))
+}
+
+// deleteKey 按已计算好的缓存键删除
+func (c *TranslationCache) deleteKey(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.cache, key)
+}
+
+// Clear 清空所有缓存条目，缓存实例清空后仍可继续使用
+func (c *TranslationCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache = make(map[string]cacheEntry)
+	c.recent = nil
+	c.recentNext = 0
+}
+
+// Len 返回缓存中未过期的条目数，包括带提示或命名空间、无法还原原文的条目
+func (c *TranslationCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock()
+	n := 0
+	for _, entry := range c.cache {
+		if now.Sub(entry.timestamp) < c.ttl {
+			n++
+		}
+	}
+	return n
+}
+
+// Keys 返回缓存中未过期条目的原文和语言对，用于调试和管理接口。
+// 缓存键是哈希值，只有记录了原文的条目（通过 Set 或不带提示、命名空间和模型的翻译写入）能够还原；结果按原文和语言对排序
+func (c *TranslationCache) Keys() []CacheKey {
+	c.mu.RLock()
+	now := c.clock()
+	var keys []CacheKey
+	for _, entry := range c.cache {
+		if entry.source == "" || now.Sub(entry.timestamp) >= c.ttl {
+			continue
+		}
+		keys = append(keys, CacheKey{Text: entry.source, InputLang: entry.inputLang, OutputLang: entry.outputLang})
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Text != keys[j].Text {
+			return keys[i].Text < keys[j].Text
+		}
+		if keys[i].InputLang != keys[j].InputLang {
+			return keys[i].InputLang < keys[j].InputLang
+		}
+		return keys[i].OutputLang < keys[j].OutputLang
+	})
+	return keys
+}
@@ -0,0 +1,708 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 是可手动推进的时钟，用于测试缓存过期
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestTranslationCache_Delete(t *testing.T) {
+	c := NewTranslationCache()
+	c.Set("Hello", "English", "Chinese", "你好")
+	c.Set("World", "English", "Chinese", "世界")
+
+	c.Delete("Hello", "English", "Chinese")
+
+	if _, ok := c.Get("Hello", "English", "Chinese"); ok {
+		t.Error("expected cache miss after Delete")
+	}
+	if result, ok := c.Get("World", "English", "Chinese"); !ok || result != "世界" {
+		t.Errorf("Delete removed unrelated entry, got %q, %v", result, ok)
+	}
+
+	// 删除不存在的条目不应 panic
+	c.Delete("Missing", "English", "Chinese")
+}
+
+func TestTranslationCache_Clear(t *testing.T) {
+	c := NewTranslationCache()
+	c.Set("Hello", "English", "Chinese", "你好")
+	c.Set("World", "English", "Chinese", "世界")
+
+	c.Clear()
+
+	if len(c.cache) != 0 {
+		t.Errorf("expected empty cache after Clear, got %d entries", len(c.cache))
+	}
+	if _, ok := c.Get("Hello", "English", "Chinese"); ok {
+		t.Error("expected cache miss after Clear")
+	}
+
+	// 清空后缓存仍然可用
+	c.Set("Hello", "English", "Chinese", "你好")
+	if result, ok := c.Get("Hello", "English", "Chinese"); !ok || result != "你好" {
+		t.Errorf("cache unusable after Clear, got %q, %v", result, ok)
+	}
+}
+
+func TestGetCacheKey(t *testing.T) {
+	if getCacheKey("a:b", "en", "zh") == getCacheKey("a", "b:en", "zh") {
+		t.Error("keys with shifted delimiters should differ")
+	}
+	if getCacheKey("Hello", "English", "Chinese") != getCacheKey("Hello", "English", "Chinese") {
+		t.Error("identical inputs should produce identical keys")
+	}
+
+	// 长文本的键长度固定
+	long := strings.Repeat("long text ", 10000)
+	if got, want := len(getCacheKey(long, "English", "Chinese")), len(getCacheKey("a", "b", "c")); got != want {
+		t.Errorf("key length = %d, want fixed length %d", got, want)
+	}
+}
+
+func TestTranslationCache_Close(t *testing.T) {
+	c := NewTranslationCache()
+
+	exited := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		c.goBackground(func(stop <-chan struct{}) {
+			<-stop
+			exited <- struct{}{}
+		})
+	}
+
+	done := make(chan error)
+	go func() { done <- c.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() did not return, background goroutines still running")
+	}
+	if len(exited) != 2 {
+		t.Errorf("expected 2 background goroutines to exit, got %d", len(exited))
+	}
+
+	// 重复关闭是安全的，关闭后缓存仍可使用
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	c.Set("Hello", "English", "Chinese", "你好")
+	if _, ok := c.Get("Hello", "English", "Chinese"); !ok {
+		t.Error("cache should remain usable after Close")
+	}
+}
+
+func TestTranslationCache_ExpiryWithClock(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now))
+
+	c.Set("Hello", "English", "Chinese", "你好")
+
+	clock.Advance(59 * time.Minute)
+	if got, ok := c.Get("Hello", "English", "Chinese"); !ok || got != "你好" {
+		t.Fatalf("Get() before TTL = %q, %v, want hit", got, ok)
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := c.Get("Hello", "English", "Chinese"); ok {
+		t.Error("entry should expire once the clock reaches the TTL")
+	}
+	if len(c.cache) != 0 {
+		t.Errorf("expired entry should be removed on read, got %d entries", len(c.cache))
+	}
+}
+
+func TestTranslationCache_SweepExpired(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTranslationCache(WithTTL(time.Minute), WithClock(clock.Now))
+
+	c.Set("Old", "English", "Chinese", "旧")
+	clock.Advance(30 * time.Second)
+	c.Set("New", "English", "Chinese", "新")
+	clock.Advance(45 * time.Second)
+
+	if removed := c.sweepExpired(); removed != 1 {
+		t.Errorf("sweepExpired() removed %d entries, want 1", removed)
+	}
+	if len(c.cache) != 1 {
+		t.Errorf("expected 1 remaining entry, got %d", len(c.cache))
+	}
+	if _, ok := c.Get("New", "English", "Chinese"); !ok {
+		t.Error("unexpired entry should survive the sweep")
+	}
+}
+
+func TestTranslationCache_BackgroundSweep(t *testing.T) {
+	clock := newFakeClock()
+	opts := []CacheOption{
+		WithTTL(time.Minute),
+		WithSweepInterval(5 * time.Millisecond),
+		WithClock(clock.Now),
+	}
+	c := NewTranslationCache(opts...)
+	defer c.Close()
+
+	c.Set("Hello", "English", "Chinese", "你好")
+	clock.Advance(2 * time.Minute)
+
+	// 不调用 Get，等待后台任务清理过期条目
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.RLock()
+		n := len(c.cache)
+		c.mu.RUnlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired entry was not swept in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNewTranslationCache_NoSweepByDefault(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTranslationCache(WithTTL(time.Minute), WithClock(clock.Now))
+	defer c.Close()
+
+	c.Set("Hello", "English", "Chinese", "你好")
+	clock.Advance(2 * time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.RLock()
+	n := len(c.cache)
+	c.mu.RUnlock()
+	if n != 1 {
+		t.Errorf("expired entry should remain until read when sweeping is off, got %d entries", n)
+	}
+}
+
+func TestCacheNormalization_Apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		norm  CacheNormalization
+		input string
+		want  string
+	}{
+		{name: "Disabled", norm: CacheNormalization{}, input: " Hello  World ", want: " Hello  World "},
+		{name: "Trim", norm: CacheNormalization{Trim: true}, input: " Hello  World \n", want: "Hello  World"},
+		{name: "Collapse Whitespace", norm: CacheNormalization{CollapseWhitespace: true}, input: " Hello \t World\n", want: "Hello World"},
+		{name: "Lowercase", norm: CacheNormalization{Trim: true, Lowercase: true}, input: "Hello ", want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.norm.apply(tt.input); got != tt.want {
+				t.Errorf("apply(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_CacheNormalization(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好", "HELLO": "你好！"})
+	ctx := context.Background()
+	trim := WithCacheNormalization(CacheNormalization{Trim: true})
+
+	if _, err := Translate(ctx, llm, "Hello ", "English", "Chinese", trim); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if !strings.Contains(llm.prompts[0], `"Hello "`) {
+		t.Errorf("expected original text to be sent to the model, got prompt: %s", llm.prompts[0])
+	}
+
+	got, err := Translate(ctx, llm, "Hello", "English", "Chinese", trim)
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "你好" || llm.Calls() != 1 {
+		t.Errorf("expected \"Hello \" and \"Hello\" to share a cache entry, got %q after %d calls", got, llm.Calls())
+	}
+
+	// 未开启规范化时大小写不同视为不同的请求
+	if _, err := Translate(ctx, llm, "HELLO", "English", "Chinese", trim); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("expected case-sensitive cache miss without Lowercase, got %d calls", llm.Calls())
+	}
+}
+
+func TestTranslate_NormalizeUnicode(t *testing.T) {
+	defaultCache.Clear()
+	nfc := "Caf\u00e9"  // é 为预组合字符
+	nfd := "Cafe\u0301" // e 加组合重音符
+	llm := newDictLLM(map[string]string{nfc: "咖啡馆", nfd: "咖啡馆（NFD）"})
+	ctx := context.Background()
+
+	if _, err := Translate(ctx, llm, nfc, "French", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	got, err := Translate(ctx, llm, nfd, "French", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "咖啡馆" || llm.Calls() != 1 {
+		t.Errorf("expected NFC and NFD forms to share a cache entry, got %q after %d calls", got, llm.Calls())
+	}
+
+	// 关闭规范化后两种写法视为不同的请求，发给模型的是原始写法
+	got, err = Translate(ctx, llm, nfd, "French", "Chinese", WithNormalizeUnicode(false))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "咖啡馆（NFD）" || llm.Calls() != 2 {
+		t.Errorf("expected a cache miss without normalization, got %q after %d calls", got, llm.Calls())
+	}
+}
+
+func TestNormalizeUnicode_AllCachePaths(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	nfc := "Caf\u00e9"
+	nfd := "Cafe\u0301"
+	llm := newDictLLM(map[string]string{nfc: "咖啡馆"})
+	ctx := context.Background()
+
+	if _, err := Translate(ctx, llm, nfc, "French", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	// 其他读取缓存的路径用 NFD 写法同样命中 Translate 以 NFC 写入的条目
+	if got, ok := Cached(nfd, "French", "Chinese"); !ok || got != "咖啡馆" {
+		t.Errorf("Cached(NFD) = %q, %v, want a hit", got, ok)
+	}
+	if got, err := TranslateBatchCombined(ctx, llm, []string{nfd}, "French", "Chinese"); err != nil || got[0] != "咖啡馆" {
+		t.Errorf("TranslateBatchCombined(NFD) = %q, %v", got, err)
+	}
+	if _, metrics, err := TranslateBatchWithMetrics(ctx, llm, []string{nfd}, "French", "Chinese"); err != nil || !metrics[0].CacheHit {
+		t.Errorf("TranslateBatchWithMetrics(NFD) metrics = %+v, %v, want a cache hit", metrics, err)
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times, want 1", llm.Calls())
+	}
+
+	// 不翻译的内容原样返回调用方的输入，不做规范化
+	code := "name := \"" + nfd + "\""
+	if got, err := Translate(ctx, llm, code, "French", "Chinese"); err != nil || got != code {
+		t.Errorf("Translate(code) = %q, %v, want the input unchanged", got, err)
+	}
+}
+
+func TestTranslationCache_GetSetMany(t *testing.T) {
+	clock := newFakeClock()
+	bulk := NewTranslationCache(WithTTL(time.Minute), WithClock(clock.Now))
+	single := NewTranslationCache(WithTTL(time.Minute), WithClock(clock.Now))
+
+	entries := map[CacheKey]string{
+		{Text: "Hello", InputLang: "English", OutputLang: "Chinese"}:  "你好",
+		{Text: "World", InputLang: "English", OutputLang: "Chinese"}:  "世界",
+		{Text: "Hello", InputLang: "English", OutputLang: "Japanese"}: "こんにちは",
+	}
+	bulk.SetMany(entries)
+	for k, result := range entries {
+		single.Set(k.Text, k.InputLang, k.OutputLang, result)
+	}
+
+	// 逐条读取批量写入的数据，与逐条写入的结果一致
+	for k, want := range entries {
+		got, ok := bulk.Get(k.Text, k.InputLang, k.OutputLang)
+		if !ok || got != want {
+			t.Errorf("Get(%+v) after SetMany = %q, %v, want %q", k, got, ok, want)
+		}
+	}
+
+	// 批量读取逐条写入的数据；未命中的键不出现在结果中
+	missing := CacheKey{Text: "Bye", InputLang: "English", OutputLang: "Chinese"}
+	keys := []CacheKey{missing}
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	got := single.GetMany(keys)
+	if len(got) != len(entries) {
+		t.Errorf("GetMany() returned %d entries, want %d", len(got), len(entries))
+	}
+	for k, want := range entries {
+		if got[k] != want {
+			t.Errorf("GetMany()[%+v] = %q, want %q", k, got[k], want)
+		}
+	}
+	if _, ok := got[missing]; ok {
+		t.Error("GetMany() returned a missing key")
+	}
+
+	// 批量写入的条目同样记录原文，可以导出
+	var buf strings.Builder
+	if err := bulk.ExportTMX(&buf, "English", "Chinese"); err != nil {
+		t.Fatalf("ExportTMX() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "<seg>World</seg>") {
+		t.Errorf("ExportTMX() missing bulk-set entry:\n%s", buf.String())
+	}
+
+	// 过期条目不返回，并被清理
+	clock.Advance(2 * time.Minute)
+	if got := bulk.GetMany(keys); len(got) != 0 {
+		t.Errorf("GetMany() after TTL = %v, want empty", got)
+	}
+	if len(bulk.cache) != 0 {
+		t.Errorf("expired entries should be removed, got %d", len(bulk.cache))
+	}
+}
+
+// benchmarkKeys 生成 n 个已写入缓存的键
+func benchmarkKeys(c *TranslationCache, n int) []CacheKey {
+	keys := make([]CacheKey, n)
+	for i := range keys {
+		keys[i] = CacheKey{Text: fmt.Sprintf("text %d", i), InputLang: "English", OutputLang: "Chinese"}
+		c.Set(keys[i].Text, keys[i].InputLang, keys[i].OutputLang, "译文")
+	}
+	return keys
+}
+
+func BenchmarkTranslationCache_Get(b *testing.B) {
+	c := NewTranslationCache()
+	keys := benchmarkKeys(c, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			c.Get(k.Text, k.InputLang, k.OutputLang)
+		}
+	}
+}
+
+func BenchmarkTranslationCache_GetMany(b *testing.B) {
+	c := NewTranslationCache()
+	keys := benchmarkKeys(c, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.GetMany(keys)
+	}
+}
+
+func BenchmarkTranslationCache_Set(b *testing.B) {
+	c := NewTranslationCache()
+	keys := benchmarkKeys(c, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			c.Set(k.Text, k.InputLang, k.OutputLang, "译文")
+		}
+	}
+}
+
+func BenchmarkTranslationCache_SetMany(b *testing.B) {
+	c := NewTranslationCache()
+	keys := benchmarkKeys(c, 100)
+	entries := make(map[CacheKey]string, len(keys))
+	for _, k := range keys {
+		entries[k] = "译文"
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.SetMany(entries)
+	}
+}
+
+func BenchmarkGetCacheKey(b *testing.B) {
+	text := strings.Repeat("Hello world. ", 20)
+	for i := 0; i < b.N; i++ {
+		getCacheKey(text, "en-US", "zh-TW")
+	}
+}
+
+func BenchmarkCached(b *testing.B) {
+	defaultCache.Clear()
+	b.Cleanup(defaultCache.Clear)
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Cached("Hello", "English", "Chinese")
+	}
+}
+
+// BenchmarkTranslate_CacheHit 与 BenchmarkCached 对比完整 Translate 路径在缓存命中时的开销
+func BenchmarkTranslate_CacheHit(b *testing.B) {
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+	defaultCache.Clear()
+	b.Cleanup(defaultCache.Clear)
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+	llm := newDictLLM(nil)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Translate(ctx, llm, "Hello", "English", "Chinese"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCached(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	if _, ok := Cached("Hello", "English", "Chinese"); ok {
+		t.Fatal("Cached() hit before translating")
+	}
+	if _, err := Translate(context.Background(), llm, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got, ok := Cached("Hello", "English", "Chinese"); !ok || got != "你好" {
+		t.Errorf("Cached() = %q, %v, want hit %q", got, ok, "你好")
+	}
+	if _, ok := Cached("Hello", "English", "French"); ok {
+		t.Error("Cached() hit for a different language pair")
+	}
+
+	// 关闭共享缓存后不再命中
+	DisableCache()
+	defer EnableCache()
+	if _, ok := Cached("Hello", "English", "Chinese"); ok {
+		t.Error("Cached() hit while the cache is disabled")
+	}
+}
+
+func TestTranslate_CacheNamespace(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+
+	reply := "你好（v1）"
+	llm := &fakeLLM{respond: func(prompt string) (string, error) { return reply, nil }}
+
+	v1, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithCacheNamespace("v1"))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	// 换一个命名空间：不命中 v1 的条目，重新调用模型
+	reply = "你好（v2）"
+	v2, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithCacheNamespace("v2"))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if v1 != "你好（v1）" || v2 != "你好（v2）" {
+		t.Errorf("namespaced results = %q, %q, want independent entries", v1, v2)
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+
+	// 两个命名空间的条目各自命中，互不覆盖
+	for ns, want := range map[string]string{"v1": "你好（v1）", "v2": "你好（v2）"} {
+		got, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithCacheNamespace(ns))
+		if err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("namespace %s = %q, want %q", ns, got, want)
+		}
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times after cache hits, want 2", n)
+	}
+
+	// 不带命名空间的条目同样独立
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("namespaced entries should not match the plain cache key")
+	}
+}
+
+// cacheLen 返回缓存中的条目数
+func cacheLen(c *TranslationCache) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.cache)
+}
+
+func TestTranslate_WithNoCache(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	for i := 0; i < 2; i++ {
+		got, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithNoCache())
+		if err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+		if got != "你好" {
+			t.Errorf("Translate() = %q, want %q", got, "你好")
+		}
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times, want 2 with caching disabled", llm.Calls())
+	}
+	if n := cacheLen(defaultCache); n != 0 {
+		t.Errorf("cache has %d entries, want 0", n)
+	}
+}
+
+func TestTranslateWithTool_CacheOptions(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"bank": "银行", "river bank": "河岸", "Hello": "你好"})
+
+	got, err := TranslateWithTool(ctx, llm, "bank", "English", "Chinese", WithHint("river bank"))
+	if err != nil {
+		t.Fatalf("TranslateWithTool() error = %v", err)
+	}
+	if got != "河岸" {
+		t.Errorf("TranslateWithTool() with hint = %q, want %q", got, "河岸")
+	}
+
+	// 带提示的译文只缓存在带提示的键下
+	if got, ok := Cached("bank", "English", "Chinese"); ok {
+		t.Errorf("Cached() = %q, want a miss for the plain key", got)
+	}
+	if got, err := TranslateWithTool(ctx, llm, "bank", "English", "Chinese"); err != nil || got != "银行" {
+		t.Errorf("TranslateWithTool() without hint = %q, %v, want %q", got, err, "银行")
+	}
+
+	// WithNoCache 的调用不写入共享缓存
+	if _, err := TranslateWithTool(ctx, llm, "Hello", "English", "Chinese", WithNoCache()); err != nil {
+		t.Fatalf("TranslateWithTool() error = %v", err)
+	}
+	if got, ok := Cached("Hello", "English", "Chinese"); ok {
+		t.Errorf("Cached() = %q after a WithNoCache call, want a miss", got)
+	}
+}
+
+func TestDisableCache(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Set("Bye", "English", "Chinese", "再见")
+	DisableCache()
+	t.Cleanup(EnableCache)
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	// 关闭时清空已有条目
+	if n := cacheLen(defaultCache); n != 0 {
+		t.Errorf("cache has %d entries after DisableCache, want 0", n)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Translate(ctx, llm, "Hello", "English", "Chinese"); err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times, want 2 with caching disabled", llm.Calls())
+	}
+
+	// Get/Set 是空操作
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("Get() hit while the cache is disabled")
+	}
+	if n := cacheLen(defaultCache); n != 0 {
+		t.Errorf("cache has %d entries, want 0", n)
+	}
+
+	// 重新开启后恢复缓存
+	EnableCache()
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); !ok {
+		t.Error("translation not cached after EnableCache")
+	}
+}
+
+func TestTranslationCache_KeysAndLen(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now))
+	if c.Len() != 0 || len(c.Keys()) != 0 {
+		t.Fatalf("new cache: Len() = %d, Keys() = %v, want empty", c.Len(), c.Keys())
+	}
+
+	c.Set("Hello", "English", "Chinese", "你好")
+	c.Set("Bye", "English", "Chinese", "再见")
+	c.Set("Hello", "English", "French", "Bonjour")
+	c.setKey(hashKeyParts("bank", "English", "Chinese", hintCacheTag, "river"), "河岸")
+
+	if c.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", c.Len())
+	}
+	want := []CacheKey{
+		{Text: "Bye", InputLang: "English", OutputLang: "Chinese"},
+		{Text: "Hello", InputLang: "English", OutputLang: "Chinese"},
+		{Text: "Hello", InputLang: "English", OutputLang: "French"},
+	}
+	if got := c.Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+
+	c.Delete("Hello", "English", "Chinese")
+	if c.Len() != 3 {
+		t.Errorf("Len() after Delete = %d, want 3", c.Len())
+	}
+	if got := c.Keys(); !reflect.DeepEqual(got, []CacheKey{want[0], want[2]}) {
+		t.Errorf("Keys() after Delete = %v", got)
+	}
+
+	// 过期条目不计入
+	clock.Advance(time.Hour)
+	if c.Len() != 0 || len(c.Keys()) != 0 {
+		t.Errorf("after TTL: Len() = %d, Keys() = %v, want empty", c.Len(), c.Keys())
+	}
+}
+
+func TestTranslationCache_Preload(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Preload(map[CacheKey]string{
+		{Text: "Hello", InputLang: "English", OutputLang: "Chinese"}:  "你好",
+		{Text: "Goodbye", InputLang: "English", OutputLang: "French"}: "Au revoir",
+	})
+
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		t.Errorf("unexpected LLM call: %s", prompt)
+		return "", nil
+	}}
+	for _, tc := range []struct{ text, out, want string }{
+		{"Hello", "Chinese", "你好"},
+		{"Goodbye", "French", "Au revoir"},
+	} {
+		got, err := Translate(context.Background(), llm, tc.text, "English", tc.out)
+		if err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("Translate(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
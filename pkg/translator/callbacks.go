@@ -0,0 +1,37 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// WithCallbacks 为翻译使用的 chain 设置回调处理器，
+// 处理器同时会收到 chain 级别和 LLM 级别的事件
+func WithCallbacks(h callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacks = h
+	}
+}
+
+// callbackModel 包装 llms.Model，在每次生成前后触发 LLM 回调
+type callbackModel struct {
+	llms.Model
+	handler callbacks.Handler
+}
+
+func (m callbackModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.handler.HandleLLMGenerateContentStart(ctx, messages)
+	resp, err := m.Model.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		m.handler.HandleLLMError(ctx, err)
+		return nil, err
+	}
+	m.handler.HandleLLMGenerateContentEnd(ctx, resp)
+	return resp, nil
+}
+
+func (m callbackModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
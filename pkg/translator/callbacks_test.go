@@ -0,0 +1,39 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTranslate_WithCallbacks(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	handler := &mockCallbackHandler{}
+
+	if _, err := Translate(context.Background(), llm, "Hello", "English", "Chinese", WithCallbacks(handler)); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if !handler.llmStartCalled || !handler.llmEndCalled {
+		t.Errorf("expected LLM start/end hooks, got start=%v end=%v", handler.llmStartCalled, handler.llmEndCalled)
+	}
+	if !handler.chainStartCalled || !handler.chainEndCalled {
+		t.Errorf("expected chain start/end hooks, got start=%v end=%v", handler.chainStartCalled, handler.chainEndCalled)
+	}
+}
+
+func TestTranslate_WithCallbacksError(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "", errors.New("provider unavailable")
+	}}
+	handler := &mockCallbackHandler{}
+
+	if _, err := Translate(context.Background(), llm, "Hello", "English", "Chinese", WithCallbacks(handler)); err == nil {
+		t.Fatal("expected error from failing LLM")
+	}
+	if !handler.llmErrorCalled {
+		t.Error("expected LLM error hook to fire")
+	}
+}
@@ -0,0 +1,73 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// statusPatterns 匹配明确描述 HTTP 状态码的片段，按顺序尝试：
+// "status code: 401"、"HTTP 429" 或 "HTTP/1.1 429"，以及 "401 Unauthorized" 这类状态码后跟标准原因短语的写法。
+// 不带这些上下文的数字（如 "read 401 bytes"、端口号、长度）不视为状态码
+var statusPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bstatus(?:\s+code)?\s*[:=]?\s*(\d{3})\b`),
+	regexp.MustCompile(`(?i)\bHTTP(?:/\d(?:\.\d)?)?\s*[:=]?\s*(\d{3})\b`),
+	regexp.MustCompile(`(?i)\b(400|401|403|429)\s+(?:Bad Request|Unauthorized|Forbidden|Too Many Requests)\b`),
+}
+
+// StatusCode 从模型提供方返回的错误中提取 HTTP 状态码，找不到时返回 0
+func StatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	msg := err.Error()
+	for _, pattern := range statusPatterns {
+		if m := pattern.FindStringSubmatch(msg); m != nil {
+			code, _ := strconv.Atoi(m[1])
+			return code
+		}
+	}
+	return 0
+}
+
+// ClassifyError 根据状态码把提供方错误包装为 ErrUnauthorized、ErrRateLimited 或 ErrBadRequest，
+// 原始错误作为被包装的原因保留；无法识别的错误原样返回
+func ClassifyError(err error) error {
+	var kind error
+	switch StatusCode(err) {
+	case 401, 403:
+		kind = ErrUnauthorized
+	case 429:
+		kind = ErrRateLimited
+	case 400:
+		kind = ErrBadRequest
+	default:
+		return err
+	}
+	if errors.Is(err, kind) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+// IsRetryable 判断错误是否值得重试：认证失败、请求参数错误、输入校验失败和
+// 上下文取消都不会因重试而成功，其余错误（限流、网络、服务端错误等）可以重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	err = ClassifyError(err)
+	switch {
+	case errors.Is(err, ErrUnauthorized),
+		errors.Is(err, ErrBadRequest),
+		errors.Is(err, ErrEmptyText),
+		errors.Is(err, ErrEmptyInputLanguage),
+		errors.Is(err, ErrEmptyOutputLanguage),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
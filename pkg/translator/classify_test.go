@@ -0,0 +1,122 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      error
+		code      int
+		retryable bool
+	}{
+		{
+			name:      "Unauthorized",
+			err:       errors.New("API returned unexpected status code: 401: Invalid token"),
+			want:      ErrUnauthorized,
+			code:      401,
+			retryable: false,
+		},
+		{
+			name:      "Rate Limited",
+			err:       errors.New("API returned unexpected status code: 429: Too many requests"),
+			want:      ErrRateLimited,
+			code:      429,
+			retryable: true,
+		},
+		{
+			name:      "Bad Request",
+			err:       fmt.Errorf("wrapped: %w", errors.New("status 400 invalid parameter")),
+			want:      ErrBadRequest,
+			code:      400,
+			retryable: false,
+		},
+		{
+			name:      "HTTP Prefix",
+			err:       errors.New("upstream returned HTTP 429, slow down"),
+			want:      ErrRateLimited,
+			code:      429,
+			retryable: true,
+		},
+		{
+			name:      "HTTP Status Line",
+			err:       errors.New("unexpected response HTTP/1.1 403"),
+			want:      ErrUnauthorized,
+			code:      403,
+			retryable: false,
+		},
+		{
+			name:      "Status Reason Phrase",
+			err:       errors.New("error, 401 Unauthorized"),
+			want:      ErrUnauthorized,
+			code:      401,
+			retryable: false,
+		},
+		{
+			name:      "Server Error",
+			err:       errors.New("API returned unexpected status code: 503"),
+			code:      503,
+			retryable: true,
+		},
+		{
+			name:      "Unknown",
+			err:       errors.New("connection reset by peer"),
+			retryable: true,
+		},
+		{
+			name:      "Byte Count Not A Status",
+			err:       errors.New("unexpected EOF: read 401 bytes"),
+			retryable: true,
+		},
+		{
+			name:      "Port Not A Status",
+			err:       errors.New("dial tcp 127.0.0.1:429: connection refused"),
+			retryable: true,
+		},
+		{
+			name:      "Length Not A Status",
+			err:       errors.New("prompt length 400 exceeds the 403 token window"),
+			retryable: true,
+		},
+		{
+			name:      "Context Canceled",
+			err:       context.Canceled,
+			retryable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			if tt.want != nil && !errors.Is(got, tt.want) {
+				t.Errorf("ClassifyError() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("ClassifyError() should wrap the original error, got %v", got)
+			}
+			if code := StatusCode(tt.err); code != tt.code {
+				t.Errorf("StatusCode() = %d, want %d", code, tt.code)
+			}
+			if retryable := IsRetryable(tt.err); retryable != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestTranslate_ClassifiedUpstreamError(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "", errors.New("API returned unexpected status code: 429: slow down")
+	}}
+
+	_, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrUpstream) {
+		t.Errorf("expected ErrRateLimited wrapped in ErrUpstream, got: %v", err)
+	}
+}
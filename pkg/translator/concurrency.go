@@ -0,0 +1,49 @@
+package translator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// concurrencyCheckTimeout 是检查时等待工作 goroutine 退出的最长时间。
+// 工作 goroutine 发送结果后才释放信号量，收齐结果时它们可能还没有完全退出
+var concurrencyCheckTimeout = time.Second
+
+// WithStrictConcurrencyChecks 开启开发期的防御性检查：批量翻译结束后确认所有工作 goroutine 已退出、
+// 信号量已全部释放，否则返回 ErrConcurrencyLeak。检查会等待工作 goroutine 退出，不建议在生产环境开启
+func WithStrictConcurrencyChecks() Option {
+	return func(o *options) {
+		o.strictConcurrencyChecks = true
+	}
+}
+
+// checkConcurrency 在开启严格检查时调用 checkDrained，否则直接返回 nil
+func (o *options) checkConcurrency(semaphore chan struct{}, workers *sync.WaitGroup) error {
+	if !o.strictConcurrencyChecks {
+		return nil
+	}
+	return checkDrained(semaphore, workers, concurrencyCheckTimeout)
+}
+
+// checkDrained 等待 workers 全部退出（最多 timeout），然后确认 semaphore 中没有残留的令牌
+func checkDrained(semaphore chan struct{}, workers *sync.WaitGroup, timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		return fmt.Errorf("%w: workers still running after %s", ErrConcurrencyLeak, timeout)
+	}
+
+	if held := len(semaphore); held != 0 {
+		return fmt.Errorf("%w: %d semaphore slots still held", ErrConcurrencyLeak, held)
+	}
+	return nil
+}
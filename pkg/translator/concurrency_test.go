@@ -0,0 +1,60 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTranslateBatch_StrictConcurrencyChecks(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	ctx := context.Background()
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("strict row %d", i)
+	}
+	llm := &fakeLLM{respond: func(prompt string) (string, error) { return "译文", nil }}
+
+	if _, err := TranslateBatch(ctx, llm, texts, "English", "Chinese", WithStrictConcurrencyChecks()); err != nil {
+		t.Fatalf("TranslateBatch() error = %v, want no leak reported", err)
+	}
+
+	// 失败的批量同样不应报告泄漏
+	defaultCache.Clear()
+	failing := &fakeLLM{respond: func(prompt string) (string, error) { return "", errors.New("boom") }}
+	_, err := TranslateBatch(ctx, failing, texts, "English", "Chinese", WithStrictConcurrencyChecks())
+	if err == nil {
+		t.Fatal("TranslateBatch() error = nil, want translation error")
+	}
+	if errors.Is(err, ErrConcurrencyLeak) {
+		t.Errorf("TranslateBatch() error = %v, want no leak reported", err)
+	}
+}
+
+func TestCheckDrained(t *testing.T) {
+	// 正常情况：没有工作 goroutine，信号量为空
+	var idle sync.WaitGroup
+	if err := checkDrained(make(chan struct{}, 2), &idle, 10*time.Millisecond); err != nil {
+		t.Errorf("checkDrained() = %v, want nil", err)
+	}
+
+	// 信号量令牌没有归还
+	held := make(chan struct{}, 2)
+	held <- struct{}{}
+	if err := checkDrained(held, &idle, 10*time.Millisecond); !errors.Is(err, ErrConcurrencyLeak) {
+		t.Errorf("checkDrained() with held slot = %v, want ErrConcurrencyLeak", err)
+	}
+
+	// 工作 goroutine 一直不退出
+	var stuck sync.WaitGroup
+	stuck.Add(1)
+	defer stuck.Done()
+	if err := checkDrained(make(chan struct{}, 2), &stuck, 10*time.Millisecond); !errors.Is(err, ErrConcurrencyLeak) {
+		t.Errorf("checkDrained() with running worker = %v, want ErrConcurrencyLeak", err)
+	}
+}
@@ -0,0 +1,89 @@
+package translator
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Pricing 是模型每 1000 个 token 的价格，提示词和生成内容分别计价
+type Pricing struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+// PricingTable 按模型名称保存价格
+type PricingTable map[string]Pricing
+
+// CostTracker 累计翻译调用消耗的 token 并按价格计算费用。
+// 同一个 CostTracker 可以在多次调用之间共享，并发安全
+type CostTracker struct {
+	mu               sync.Mutex
+	pricing          Pricing
+	promptTokens     int
+	completionTokens int
+}
+
+// NewCostTracker 使用价格表中 model 对应的价格创建 CostTracker
+func NewCostTracker(table PricingTable, model string) (*CostTracker, error) {
+	pricing, ok := table[model]
+	if !ok {
+		return nil, fmt.Errorf("no pricing for model %q", model)
+	}
+	return &CostTracker{pricing: pricing}, nil
+}
+
+// WithCostTracker 把每次 LLM 调用报告的 token 用量累计到 c 中
+func WithCostTracker(c *CostTracker) Option {
+	return func(o *options) {
+		o.costTracker = c
+	}
+}
+
+// Tokens 返回累计的提示词和生成内容 token 数
+func (c *CostTracker) Tokens() (prompt, completion int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.promptTokens, c.completionTokens
+}
+
+// Cost 返回按价格计算的累计费用
+func (c *CostTracker) Cost() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return float64(c.promptTokens)/1000*c.pricing.PromptPer1K +
+		float64(c.completionTokens)/1000*c.pricing.CompletionPer1K
+}
+
+// Reset 清零累计的 token 数
+func (c *CostTracker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.promptTokens, c.completionTokens = 0, 0
+}
+
+// add 从模型返回的 GenerationInfo 中读取 token 用量并累加，缺少的字段按 0 计
+func (c *CostTracker) add(info map[string]any) {
+	prompt := intFromInfo(info, "PromptTokens")
+	completion := intFromInfo(info, "CompletionTokens")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.promptTokens += prompt
+	c.completionTokens += completion
+}
+
+// intFromInfo 读取 GenerationInfo 中的整数字段，兼容不同提供方使用的数值类型
+func intFromInfo(info map[string]any, key string) int {
+	switch v := info[key].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,66 @@
+package translator
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestCostTracker(t *testing.T) {
+	defaultCache.Clear()
+	table := PricingTable{
+		"small-model": {PromptPer1K: 0.5, CompletionPer1K: 1.5},
+		"large-model": {PromptPer1K: 5, CompletionPer1K: 15},
+	}
+	tracker, err := NewCostTracker(table, "small-model")
+	if err != nil {
+		t.Fatalf("NewCostTracker() error = %v", err)
+	}
+
+	llm := newDictLLM(map[string]string{"Hello": "你好", "Thank you": "谢谢"})
+	llm.info = map[string]any{"PromptTokens": 200, "CompletionTokens": 100, "TotalTokens": 300}
+
+	ctx := context.Background()
+	for _, text := range []string{"Hello", "Thank you"} {
+		if _, err := Translate(ctx, llm, text, "English", "Chinese", WithCostTracker(tracker)); err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+	}
+
+	prompt, completion := tracker.Tokens()
+	if prompt != 400 || completion != 200 {
+		t.Errorf("Tokens() = (%d, %d), want (400, 200)", prompt, completion)
+	}
+	// 0.4k * 0.5 + 0.2k * 1.5 = 0.5
+	if got := tracker.Cost(); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Cost() = %v, want 0.5", got)
+	}
+
+	// 缓存命中不会产生 LLM 调用，也不计费
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithCostTracker(tracker)); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := tracker.Cost(); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Cost() after cache hit = %v, want 0.5", got)
+	}
+
+	tracker.Reset()
+	if got := tracker.Cost(); got != 0 {
+		t.Errorf("Cost() after Reset = %v, want 0", got)
+	}
+}
+
+func TestNewCostTracker_UnknownModel(t *testing.T) {
+	if _, err := NewCostTracker(PricingTable{"known": {}}, "unknown"); err == nil {
+		t.Error("expected error for model missing from pricing table")
+	}
+}
+
+func TestIntFromInfo(t *testing.T) {
+	info := map[string]any{"a": 3, "b": int64(4), "c": float64(5), "d": "6"}
+	for key, want := range map[string]int{"a": 3, "b": 4, "c": 5, "d": 0, "missing": 0} {
+		if got := intFromInfo(info, key); got != want {
+			t.Errorf("intFromInfo(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateCSV 读取 CSV，翻译第 srcCol 列（从 0 开始），并在每行末尾追加译文列后写入 w。
+// 第一行视为表头，追加的列名为 "<原列名> (<目标语言>)"。相同的文本只翻译一次。
+func TranslateCSV(ctx context.Context, llm llms.Model, r io.Reader, w io.Writer, srcCol int, inputLanguage string, outputLanguage string) error {
+	if srcCol < 0 {
+		return fmt.Errorf("invalid source column %d", srcCol)
+	}
+
+	reader := csv.NewReader(r)
+	var rows [][]string
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return fmt.Errorf("malformed CSV at row %d: %w", row, parseErr.Err)
+			}
+			return fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+		if srcCol >= len(record) {
+			return fmt.Errorf("row %d has %d columns, source column %d out of range", row, len(record), srcCol)
+		}
+		rows = append(rows, record)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("empty CSV input")
+	}
+
+	// 去重后批量翻译
+	var texts []string
+	seen := make(map[string]bool)
+	for _, record := range rows[1:] {
+		text := record[srcCol]
+		if strings.TrimSpace(text) == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+
+	translations := make(map[string]string, len(texts))
+	if len(texts) > 0 {
+		results, err := TranslateBatch(ctx, llm, texts, inputLanguage, outputLanguage)
+		if err != nil {
+			return err
+		}
+		for i, text := range texts {
+			translations[text] = results[i]
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	header := append(rows[0], fmt.Sprintf("%s (%s)", rows[0][srcCol], outputLanguage))
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for i, record := range rows[1:] {
+		if err := writer.Write(append(record, translations[record[srcCol]])); err != nil {
+			return fmt.Errorf("failed to write CSV row %d: %w", i+2, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateCSV(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world": "你好，世界",
+		"Thank you":   "谢谢",
+	})
+
+	input := "id,text,note\n1,Hello world,greeting\n2,Thank you,\"polite, formal\"\n3,Hello world,repeat\n4,,empty\n"
+	var out strings.Builder
+	if err := TranslateCSV(context.Background(), llm, strings.NewReader(input), &out, 1, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateCSV() error = %v", err)
+	}
+
+	want := "id,text,note,text (Chinese)\n1,Hello world,greeting,你好，世界\n2,Thank you,\"polite, formal\",谢谢\n3,Hello world,repeat,你好，世界\n4,,empty,\n"
+	if out.String() != want {
+		t.Errorf("TranslateCSV() =\n%s\nwant\n%s", out.String(), want)
+	}
+	// 重复的文本只翻译一次
+	if llm.Calls() != 2 {
+		t.Errorf("expected 2 LLM calls after dedup, got %d", llm.Calls())
+	}
+}
+
+func TestTranslateCSV_Errors(t *testing.T) {
+	withoutBatchDelay(t)
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	tests := []struct {
+		name          string
+		input         string
+		srcCol        int
+		errorContains string
+	}{
+		{
+			name:          "Wrong Field Count",
+			input:         "id,text\n1,Hello\n2,Hello,extra\n",
+			srcCol:        1,
+			errorContains: "row 3",
+		},
+		{
+			name:          "Bare Quote",
+			input:         "id,text\n1,Hel\"lo\n",
+			srcCol:        1,
+			errorContains: "row 2",
+		},
+		{
+			name:          "Column Out Of Range",
+			input:         "id,text\n1,Hello\n",
+			srcCol:        5,
+			errorContains: "out of range",
+		},
+		{
+			name:          "Empty Input",
+			input:         "",
+			srcCol:        0,
+			errorContains: "empty CSV",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := TranslateCSV(context.Background(), llm, strings.NewReader(tt.input), &out, tt.srcCol, "English", "Chinese")
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("expected error containing %q, got: %v", tt.errorContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	detectCacheTag      = "detect-language"  // 语言检测结果缓存键的前缀
+	detectCacheDuration = 7 * 24 * time.Hour // 语言检测结果的缓存有效期，文本的语言不会变化，比译文保留得更久
+)
+
+// detectCache 单独保存语言检测结果，与译文缓存分开，有自己的有效期，清空译文缓存时不受影响
+var detectCache = NewTranslationCache(WithTTL(detectCacheDuration))
+
+// WithDetectionCache 用 c 代替默认的语言检测缓存，例如使用不同的有效期，或在多个调用之间隔离检测结果
+func WithDetectionCache(c *TranslationCache) Option {
+	return func(o *options) {
+		o.detectCache = c
+	}
+}
+
+// detectionCache 返回保存语言检测结果的缓存
+func (o *options) detectionCache() *TranslationCache {
+	if o.noCache {
+		return uncached
+	}
+	if o.detectCache != nil {
+		return o.detectCache
+	}
+	return detectCache
+}
+
+// WithSkipTargetLanguage 在批量和长文本翻译前先检测每段文本的语言，
+// 已经是目标语言的片段原样保留，不再发给模型翻译
+func WithSkipTargetLanguage() Option {
+	return func(o *options) {
+		o.skipTargetLanguage = true
+	}
+}
+
+// DetectLanguage 让模型识别文本的语言，返回语言的英文名称（如 "English"、"Chinese"）。
+// 检测结果写入单独的语言检测缓存（见 WithDetectionCache），有效期内同一段文本只检测一次
+func DetectLanguage(ctx context.Context, llm llms.Model, text string, opts ...Option) (string, error) {
+	return detectLanguage(ctx, llm, text, newOptions(opts))
+}
+
+func detectLanguage(ctx context.Context, llm llms.Model, text string, o *options) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", ErrEmptyText
+	}
+
+	cache := o.detectionCache()
+	key := hashKeyParts(detectCacheTag, text)
+	if language, ok := cache.getKey(key); ok {
+		return language, nil
+	}
+
+	reply, err := runPrompt(ctx, llm, o,
+		`Identify the language of the following text. Reply with the English name of the language only, such as "English" or "Chinese".
+Text: {{.text}}`,
+		map[string]any{
+			"text": text,
+		})
+	if err != nil {
+		return "", fmt.Errorf("language detection failed: %w", err)
+	}
+
+	language := strings.Trim(strings.TrimSpace(reply), `"'.。`)
+	if language == "" {
+		return "", fmt.Errorf("no language found in reply: %q", reply)
+	}
+	cache.setKey(key, language)
+	return language, nil
+}
+
+// alreadyInLanguage 在启用 WithSkipTargetLanguage 时判断 text 是否已经是目标语言。
+// 检测失败时只记录日志并返回 false，让调用方照常翻译
+func alreadyInLanguage(ctx context.Context, llm llms.Model, text string, outputLanguage string, o *options) bool {
+	if !o.skipTargetLanguage {
+		return false
+	}
+	language, err := detectLanguage(ctx, llm, text, o)
+	if err != nil {
+		log.Printf("Language detection failed for '%s': %v", text, err)
+		return false
+	}
+	// 检测结果是语言名称，目标语言可能是 BCP-47 标签，按基础语言比较
+	return sameBaseLanguage(language, outputLanguage)
+}
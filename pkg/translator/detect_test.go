@@ -0,0 +1,159 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// newDetectLLM 创建一个 fakeLLM：语言检测的 prompt 按是否含有汉字回复语言，其余 prompt 按词典翻译
+func newDetectLLM(dict map[string]string) *fakeLLM {
+	translate := newDictLLM(dict)
+	return &fakeLLM{
+		respond: func(prompt string) (string, error) {
+			if !strings.Contains(prompt, "Identify the language") {
+				return translate.respond(prompt)
+			}
+			for _, r := range prompt {
+				if unicode.Is(unicode.Han, r) {
+					return "Chinese", nil
+				}
+			}
+			return "English.", nil
+		},
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	defaultCache.Clear()
+	detectCache.Clear()
+	llm := newDetectLLM(nil)
+	ctx := context.Background()
+
+	got, err := DetectLanguage(ctx, llm, "Hello")
+	if err != nil {
+		t.Fatalf("DetectLanguage() error = %v", err)
+	}
+	if got != "English" {
+		t.Errorf("DetectLanguage() = %q, want %q", got, "English")
+	}
+
+	// 同一段文本的检测结果被缓存
+	if _, err := DetectLanguage(ctx, llm, "Hello"); err != nil {
+		t.Fatalf("DetectLanguage() error = %v", err)
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("expected detection to be cached, got %d LLM calls", llm.Calls())
+	}
+
+	if _, err := DetectLanguage(ctx, llm, " "); err == nil {
+		t.Error("expected error for empty text")
+	}
+}
+
+func TestDetectLanguage_SeparateCache(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	cache := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now))
+	llm := newDetectLLM(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := DetectLanguage(ctx, llm, "Good morning", WithDetectionCache(cache)); err != nil {
+			t.Fatalf("DetectLanguage() error = %v", err)
+		}
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times, want 1 (second detection is a cache hit)", llm.Calls())
+	}
+
+	// 清空译文缓存不影响检测结果
+	defaultCache.Clear()
+	if _, err := DetectLanguage(ctx, llm, "Good morning", WithDetectionCache(cache)); err != nil {
+		t.Fatalf("DetectLanguage() error = %v", err)
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times after clearing the translation cache, want 1", llm.Calls())
+	}
+
+	// 检测缓存按自己的有效期过期
+	clock.Advance(time.Hour)
+	if _, err := DetectLanguage(ctx, llm, "Good morning", WithDetectionCache(cache)); err != nil {
+		t.Fatalf("DetectLanguage() error = %v", err)
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times after the detection TTL, want 2", llm.Calls())
+	}
+}
+
+func TestTranslateBatchCombined_SkipTargetLanguage(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDetectLLM(nil)
+	translateCalls := 0
+	detect := llm.respond
+	llm.respond = func(prompt string) (string, error) {
+		if strings.Contains(prompt, "numbered list") {
+			translateCalls++
+			if strings.Contains(prompt, "已经是中文") {
+				t.Errorf("text already in target language was sent for translation: %s", prompt)
+			}
+			return "1. 你好\n2. 谢谢", nil
+		}
+		return detect(prompt)
+	}
+
+	texts := []string{"Hello", "已经是中文", "Thank you"}
+	got, err := TranslateBatchCombined(context.Background(), llm, texts, "English", "Chinese", WithSkipTargetLanguage())
+	if err != nil {
+		t.Fatalf("TranslateBatchCombined() error = %v", err)
+	}
+	if want := []string{"你好", "已经是中文", "谢谢"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatchCombined() = %q, want %q", got, want)
+	}
+	if translateCalls != 1 {
+		t.Errorf("expected 1 translation call, got %d", translateCalls)
+	}
+}
+
+func TestTranslateBatch_SkipTargetLanguage(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDetectLLM(map[string]string{"Hello": "你好", "Thank you": "谢谢"})
+
+	texts := []string{"Hello", "已经是中文", "Thank you"}
+	got, err := TranslateBatch(context.Background(), llm, texts, "English", "Chinese", WithSkipTargetLanguage())
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"你好", "已经是中文", "谢谢"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %q, want %q", got, want)
+	}
+	for _, prompt := range llm.prompts {
+		if strings.Contains(prompt, "已经是中文") && !strings.Contains(prompt, "Identify the language") {
+			t.Errorf("text already in target language was sent for translation: %s", prompt)
+		}
+	}
+}
+
+func TestTranslateBatch_SkipTargetLanguageLocaleTag(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDetectLLM(map[string]string{"Hello": "你好"})
+
+	// 检测结果是 "Chinese"，目标语言是 BCP-47 标签 zh-CN
+	texts := []string{"Hello", "已经是中文"}
+	got, err := TranslateBatch(context.Background(), llm, texts, "English", "zh-CN", WithSkipTargetLanguage())
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"你好", "已经是中文"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %q, want %q", got, want)
+	}
+	for _, prompt := range llm.prompts {
+		if strings.Contains(prompt, "已经是中文") && !strings.Contains(prompt, "Identify the language") {
+			t.Errorf("text already in the target language was sent for translation: %s", prompt)
+		}
+	}
+}
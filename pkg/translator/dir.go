@@ -0,0 +1,182 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// dirFileExtensions 是 TranslateDir 处理的文件类型
+var dirFileExtensions = map[string]bool{
+	".txt": true,
+	".md":  true,
+}
+
+// TranslateDir 翻译 root 下所有文件名匹配 glob（如 "*.md"，为空时匹配全部）的 .txt 和 .md 文件，
+// 把译文写到同一目录下的 name.<lang>.ext，例如 README.md 翻译成 Chinese 时写入 README.zh.md。
+// 源语言按每个文件的第一段自动检测；已经是目标语言的文件、本身就是译文的文件（如 README.ja.md）
+// 以及译文比原文新的文件会被跳过。Markdown 文件使用 TranslateMarkdown 保留格式，其余按长文本翻译。
+// 文件并发数与批量翻译相同；返回写入的译文路径（按路径排序），单个文件失败不影响其他文件，
+// 所有失败合并为一个错误返回
+func TranslateDir(ctx context.Context, llm llms.Model, root string, glob string, outputLanguage string, opts ...Option) ([]string, error) {
+	if outputLanguage == "" {
+		return nil, ErrEmptyOutputLanguage
+	}
+	if glob != "" {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+
+	code := languageCode(outputLanguage)
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !dirFileExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		if glob != "" {
+			if ok, _ := filepath.Match(glob, d.Name()); !ok {
+				return nil
+			}
+		}
+		if isTranslatedFile(d.Name(), code) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	o := newOptions(opts)
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		written []string
+		errs    []error
+	)
+
+	// 限制并发数
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	for _, path := range files {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+
+			// 获取信号量
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			outPath, ok, err := translateFile(ctx, llm, path, outputLanguage, code, o, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to translate %s: %w", path, err))
+				return
+			}
+			if ok {
+				written = append(written, outPath)
+			}
+		}(path)
+	}
+
+	wg.Wait()
+	sort.Strings(written)
+	return written, errors.Join(errs...)
+}
+
+// translateFile 翻译单个文件并写入译文，返回译文路径以及是否实际写入
+func translateFile(ctx context.Context, llm llms.Model, path string, outputLanguage string, code string, o *options, opts []Option) (string, bool, error) {
+	ext := filepath.Ext(path)
+	outPath := strings.TrimSuffix(path, ext) + "." + code + ext
+
+	srcInfo, err := os.Stat(path)
+	if err != nil {
+		return "", false, err
+	}
+	if outInfo, err := os.Stat(outPath); err == nil && !outInfo.ModTime().Before(srcInfo.ModTime()) {
+		log.Printf("Skipping %s: %s is up to date", path, outPath)
+		return outPath, false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	text := string(content)
+	sample := firstParagraph(text)
+	if sample == "" {
+		return outPath, false, nil
+	}
+
+	inputLanguage, err := detectLanguage(ctx, llm, sample, o)
+	if err != nil {
+		return "", false, err
+	}
+	if sameBaseLanguage(inputLanguage, outputLanguage) {
+		log.Printf("Skipping %s: already in %s", path, outputLanguage)
+		return outPath, false, nil
+	}
+
+	var translated string
+	if strings.EqualFold(ext, ".md") {
+		translated, err = TranslateMarkdown(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+	} else {
+		translated, err = TranslateLongText(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := os.WriteFile(outPath, []byte(translated), srcInfo.Mode().Perm()); err != nil {
+		return "", false, err
+	}
+	return outPath, true, nil
+}
+
+// firstParagraph 返回文本中第一个非空段落，用于检测文件的语言
+func firstParagraph(text string) string {
+	for _, chunk := range (ParagraphSplitter{}).Split(text) {
+		if chunk = strings.TrimSpace(chunk); chunk != "" {
+			return chunk
+		}
+	}
+	return ""
+}
+
+// languageCode 返回语言名称对应的代码（如 Chinese -> zh），用于译文文件名；
+// 无法识别的名称转为小写并去掉空格
+func languageCode(language string) string {
+	for code, name := range tmxLanguageNames {
+		if strings.EqualFold(name, language) {
+			return code
+		}
+	}
+	return strings.ToLower(strings.ReplaceAll(language, " ", ""))
+}
+
+// isTranslatedFile 判断文件名是否为 name.<lang>.ext 形式的译文，
+// lang 为已知的语言代码或本次的目标语言代码
+func isTranslatedFile(name string, code string) bool {
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	lang := strings.TrimPrefix(filepath.Ext(stem), ".")
+	if lang == "" {
+		return false
+	}
+	_, known := tmxLanguageNames[strings.ToLower(lang)]
+	return known || strings.EqualFold(lang, code)
+}
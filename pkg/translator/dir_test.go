@@ -0,0 +1,174 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// newDirLLM 返回按内容检测语言、按词典翻译的 fake LLM
+func newDirLLM(dict map[string]string) *fakeLLM {
+	translate := newDictLLM(dict).respond
+	return &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.HasPrefix(prompt, "Identify the language") {
+			if strings.IndexFunc(prompt, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 {
+				return "Chinese", nil
+			}
+			return "English", nil
+		}
+		return translate(prompt)
+	}}
+}
+
+// writeFiles 在 root 下按相对路径写入文件
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(content)
+}
+
+func TestTranslateDir(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"notes.txt":         "Hello world\n",
+		"docs/README.md":    "# Title\n\nHello world\n",
+		"docs/README.ja.md": "# タイトル\n",
+		"docs/zh.txt":       "你好，世界\n",
+		"image.png":         "not text",
+	})
+	llm := newDirLLM(map[string]string{
+		"Hello world": "你好，世界",
+		"Title":       "标题",
+	})
+
+	written, err := TranslateDir(ctx, llm, root, "", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "docs", "README.zh.md"),
+		filepath.Join(root, "notes.zh.txt"),
+	}
+	if strings.Join(written, ",") != strings.Join(want, ",") {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+	if got := readFile(t, want[0]); got != "# 标题\n\n你好，世界\n" {
+		t.Errorf("README.zh.md = %q", got)
+	}
+	if got := readFile(t, want[1]); got != "你好，世界\n" {
+		t.Errorf("notes.zh.txt = %q", got)
+	}
+	// 已经是目标语言的文件和已有的译文不再翻译
+	for _, name := range []string{"docs/zh.zh.txt", "docs/README.ja.zh.md"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			t.Errorf("unexpected output %s", name)
+		}
+	}
+}
+
+func TestTranslateDir_SkipsUpToDate(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"a.txt": "Hello world"})
+	llm := newDirLLM(map[string]string{"Hello world": "你好，世界"})
+
+	if _, err := TranslateDir(ctx, llm, root, "*.txt", "Chinese"); err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+	calls := llm.Calls()
+
+	// 译文比原文新：跳过
+	written, err := TranslateDir(ctx, llm, root, "*.txt", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+	if len(written) != 0 || llm.Calls() != calls {
+		t.Errorf("up-to-date file re-translated: written = %v, calls %d -> %d", written, calls, llm.Calls())
+	}
+
+	// 原文更新后重新翻译
+	defaultCache.Clear()
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "a.txt"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	written, err = TranslateDir(ctx, llm, root, "*.txt", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+	if len(written) != 1 {
+		t.Errorf("written = %v, want the stale translation rewritten", written)
+	}
+}
+
+func TestTranslateDir_Glob(t *testing.T) {
+	defaultCache.Clear()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a.md":  "Hello world",
+		"b.txt": "Hello world",
+	})
+	llm := newDirLLM(map[string]string{"Hello world": "你好，世界"})
+
+	written, err := TranslateDir(context.Background(), llm, root, "*.md", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+	if len(written) != 1 || filepath.Base(written[0]) != "a.zh.md" {
+		t.Errorf("written = %v, want only a.zh.md", written)
+	}
+
+	if _, err := TranslateDir(context.Background(), llm, root, "[", "Chinese"); err == nil {
+		t.Error("expected error for invalid glob")
+	}
+}
+
+func TestTranslateDir_MarkdownOptions(t *testing.T) {
+	defaultCache.Clear()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"README.md": "# Title\n\nHello world\n"})
+	llm := newDirLLM(map[string]string{"Hello world": "你好，世界", "Title": "标题"})
+
+	if _, err := TranslateDir(context.Background(), llm, root, "", "Chinese", WithModel("gpt-4o")); err != nil {
+		t.Fatalf("TranslateDir() error = %v", err)
+	}
+
+	// Markdown 文件的每一段正文翻译都带上调用方的选项
+	translated := 0
+	for i, prompt := range llm.prompts {
+		if strings.HasPrefix(prompt, "Identify the language") {
+			continue
+		}
+		translated++
+		if llm.options[i].Model != "gpt-4o" {
+			t.Errorf("translation call %q used model %q, want %q", prompt, llm.options[i].Model, "gpt-4o")
+		}
+	}
+	if translated != 2 {
+		t.Errorf("got %d translation calls, want 2", translated)
+	}
+}
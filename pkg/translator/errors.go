@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 翻译失败时返回的错误，可通过 errors.Is 判断
+var (
+	// ErrEmptyText 表示待翻译的文本为空
+	ErrEmptyText = errors.New("empty text input")
+	// ErrEmptyInputLanguage 表示未指定源语言
+	ErrEmptyInputLanguage = errors.New("empty input language")
+	// ErrEmptyOutputLanguage 表示未指定目标语言
+	ErrEmptyOutputLanguage = errors.New("empty output language")
+	// ErrUpstream 表示模型提供方调用失败，原始错误被一并包装
+	ErrUpstream = errors.New("upstream provider error")
+	// ErrUnauthorized 表示提供方拒绝了认证（HTTP 401/403）
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited 表示请求被提供方限流（HTTP 429）
+	ErrRateLimited = errors.New("rate limited")
+	// ErrBadRequest 表示提供方认为请求参数有误（HTTP 400）
+	ErrBadRequest = errors.New("bad request")
+	// ErrLowQuality 表示译文的质量评分低于设定的阈值
+	ErrLowQuality = errors.New("translation quality below threshold")
+	// ErrEmptyResponse 表示模型重试后仍返回空内容
+	ErrEmptyResponse = errors.New("empty response from model")
+	// ErrScriptMismatch 表示输入文本的书写系统与声明的源语言不符
+	ErrScriptMismatch = errors.New("input script does not match input language")
+	// ErrUnhealthy 表示健康检查未通过：模型不可达或返回了异常的结果
+	ErrUnhealthy = errors.New("health check failed")
+	// ErrEcho 表示开启 WithEchoGuard 后，模型在重试后仍原样返回了原文
+	ErrEcho = errors.New("model echoed the input instead of translating")
+	// ErrConcurrencyLeak 表示批量翻译结束后仍有信号量未释放或工作 goroutine 未退出，属于内部错误
+	ErrConcurrencyLeak = errors.New("concurrency leak detected")
+)
+
+// RequestError 在提供方以 HTTP 400 拒绝请求时附带脱敏后的请求概要（不含 API key 和原文），
+// 便于定位被拒绝的参数。可通过 errors.As 取出，原始错误仍可通过 errors.Is 判断
+type RequestError struct {
+	Model          string // 请求使用的模型，未通过 WithModel 或 WithModelOverride 指定时为空，表示客户端的默认模型
+	InputLanguage  string // 源语言参数
+	OutputLanguage string // 目标语言参数
+	TextLength     int    // 原文的字符数
+	PromptLength   int    // 发送的提示词（含 system 消息）的字符数
+	MaxTokens      int    // 最大输出 token 数，0 表示未设置
+	Err            error  // 被包装的错误
+}
+
+func (e *RequestError) Error() string {
+	model := e.Model
+	if model == "" {
+		model = "default"
+	}
+	return fmt.Sprintf("%v (model=%s, input=%q, output=%q, text_length=%d, prompt_length=%d, max_tokens=%d)",
+		e.Err, model, e.InputLanguage, e.OutputLanguage, e.TextLength, e.PromptLength, e.MaxTokens)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,103 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTranslate_TypedErrors(t *testing.T) {
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		text          string
+		inputLang     string
+		outputLang    string
+		wantErr       error
+		errorContains string
+	}{
+		{name: "Empty Text", text: "", inputLang: "English", outputLang: "Chinese", wantErr: ErrEmptyText, errorContains: "empty text"},
+		{name: "Empty Input Language", text: "Hello", inputLang: "", outputLang: "Chinese", wantErr: ErrEmptyInputLanguage, errorContains: "empty input language"},
+		{name: "Empty Output Language", text: "Hello", inputLang: "English", outputLang: "", wantErr: ErrEmptyOutputLanguage, errorContains: "empty output language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for fn, translate := range map[string]func() (string, error){
+				"Translate": func() (string, error) {
+					return Translate(ctx, llm, tt.text, tt.inputLang, tt.outputLang)
+				},
+				"TranslateWithTool": func() (string, error) {
+					return TranslateWithTool(ctx, llm, tt.text, tt.inputLang, tt.outputLang)
+				},
+			} {
+				_, err := translate()
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s() error = %v, want %v", fn, err, tt.wantErr)
+				}
+				// 保留原有的错误信息以兼容按字符串匹配的调用方
+				if err != nil && !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("%s() error = %q, want substring %q", fn, err, tt.errorContains)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslate_UpstreamError(t *testing.T) {
+	defaultCache.Clear()
+	cause := errors.New("connection reset")
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "", cause
+	}}
+
+	_, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+	if !errors.Is(err, ErrUpstream) {
+		t.Errorf("expected ErrUpstream, got: %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected original cause to be wrapped, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "translation failed") {
+		t.Errorf("expected compatible message, got: %v", err)
+	}
+}
+
+func TestTranslate_BadRequestCarriesRequestContext(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(string) (string, error) {
+		return "", errors.New("API returned unexpected status code: 400: invalid max_tokens")
+	}}
+	ctx := WithModelOverride(context.Background(), "qwen-test")
+
+	_, err := Translate(ctx, llm, "Hello", "en-US", "zh-TW", WithMaxTokens(5))
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("Translate() error = %v, want ErrBadRequest", err)
+	}
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Translate() error = %v, want *RequestError", err)
+	}
+	want := RequestError{Model: "qwen-test", InputLanguage: "en-US", OutputLanguage: "zh-TW", TextLength: 5, MaxTokens: 5}
+	if reqErr.Model != want.Model || reqErr.InputLanguage != want.InputLanguage || reqErr.OutputLanguage != want.OutputLanguage ||
+		reqErr.TextLength != want.TextLength || reqErr.MaxTokens != want.MaxTokens {
+		t.Errorf("RequestError = %+v, want %+v", *reqErr, want)
+	}
+	if reqErr.PromptLength <= reqErr.TextLength {
+		t.Errorf("PromptLength = %d, want the length of the whole prompt", reqErr.PromptLength)
+	}
+	for _, s := range []string{"model=qwen-test", `input="en-US"`, `output="zh-TW"`, "max_tokens=5"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error message %q does not contain %q", err.Error(), s)
+		}
+	}
+
+	// 其他错误不附带请求概要
+	llm.respond = func(string) (string, error) { return "", errors.New("status code: 500") }
+	if _, err := Translate(ctx, llm, "Hello", "en-US", "zh-TW"); errors.As(err, &reqErr) {
+		t.Errorf("unexpected RequestError for a 500 error: %v", err)
+	}
+}
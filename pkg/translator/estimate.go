@@ -0,0 +1,21 @@
+package translator
+
+import "strings"
+
+// EstimateTokens 粗略估算把 text 从 inputLanguage 翻译为 outputLanguage 时提示词的 token 数，
+// 包括翻译模板（含按语言对注册的模板）本身的开销。不计入模型输出的译文，也不调用分词器，
+// 结果只适合用来判断文本是否需要走 TranslateLongText 分段翻译
+func EstimateTokens(text string, inputLanguage string, outputLanguage string) int {
+	values := describeLanguageValues(map[string]any{
+		"text":           text,
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+	})
+	template := promptFor(inputLanguage, outputLanguage)
+	prompt, err := formatPrompt(template, values)
+	if err != nil {
+		// 注册的模板用到了其他变量时无法渲染，退回按模板加原文估算
+		prompt = strings.Join([]string{template, text}, "\n")
+	}
+	return estimateTokens(prompt)
+}
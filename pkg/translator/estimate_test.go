@@ -0,0 +1,43 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	// 文本越长估算值越大
+	prev := EstimateTokens("", "English", "Chinese")
+	if prev <= 0 {
+		t.Fatalf("EstimateTokens(\"\") = %d, want the prompt overhead to be counted", prev)
+	}
+	for n := 1; n <= 64; n *= 2 {
+		got := EstimateTokens(strings.Repeat("hello world ", n), "English", "Chinese")
+		if got <= prev {
+			t.Errorf("EstimateTokens(%d repeats) = %d, want more than %d", n, got, prev)
+		}
+		prev = got
+	}
+
+	// ASCII 文本约 4 个字符一个 token
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	textTokens := EstimateTokens(text, "English", "Chinese") - EstimateTokens("", "English", "Chinese")
+	if low, high := len(text)/6, len(text)/3; textTokens < low || textTokens > high {
+		t.Errorf("%d ASCII characters estimated at %d tokens, want between %d and %d", len(text), textTokens, low, high)
+	}
+
+	// 中日韩文字每个字符都计入
+	if got, want := EstimateTokens("你好世界", "Chinese", "English")-EstimateTokens("", "Chinese", "English"), 4; got < want {
+		t.Errorf("EstimateTokens for 4 Han characters = %d, want at least %d", got, want)
+	}
+}
+
+func TestEstimateTokens_RegisteredPrompt(t *testing.T) {
+	before := EstimateTokens("Hello", "English", "Latin")
+	RegisterPrompt("English", "Latin", defaultPromptTemplate+" Use classical grammar and vocabulary, and prefer the forms found in Cicero and Caesar over later ecclesiastical usage.")
+	t.Cleanup(func() { RegisterPrompt("English", "Latin", "") })
+
+	if got := EstimateTokens("Hello", "English", "Latin"); got <= before {
+		t.Errorf("EstimateTokens with a longer registered template = %d, want more than %d", got, before)
+	}
+}
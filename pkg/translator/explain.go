@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// explainPrompt 要求模型以 JSON 同时返回译文和语法、习语说明
+const explainPrompt = `Translate the following text from {{.inputLanguage}} to {{.outputLanguage}}, then briefly explain the grammar and idioms a learner should notice, written in {{.inputLanguage}}.
+Reply with a JSON object only, in the form {"translation": "...", "explanation": "..."}.
+Text: {{.text}}`
+
+// explainedReply 是 explainPrompt 期望的回复格式
+type explainedReply struct {
+	Translation string `json:"translation"`
+	Explanation string `json:"explanation"`
+}
+
+// TranslateWithExplanation 翻译文本并附带简短的语法和习语说明，适合语言学习场景。
+// 说明使用源语言书写。只有译文写入缓存，说明不缓存，所以每次调用都会请求模型
+func TranslateWithExplanation(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (translation string, explanation string, err error) {
+	// 验证输入
+	if text == "" {
+		return "", "", ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return "", "", ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return "", "", ErrEmptyOutputLanguage
+	}
+
+	o := newOptions(opts)
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"text":           text,
+	}
+	out, err := runPrompt(ctx, llm, o, o.withHint(explainPrompt, values), values)
+	if err != nil {
+		return "", "", fmt.Errorf("translation failed: %w", err)
+	}
+
+	reply, err := parseExplainedReply(out)
+	if err != nil {
+		return "", "", err
+	}
+	translation, err = o.parse(reply.Translation)
+	if err != nil {
+		return "", "", err
+	}
+	if translation == "" {
+		return "", "", fmt.Errorf("translation failed: %w", ErrEmptyResponse)
+	}
+	translation = o.postEditText(translation, inputLanguage, outputLanguage)
+
+	o.cacheSet(ctx, o.cacheNormalization.apply(text), inputLanguage, outputLanguage, translation)
+	return translation, strings.TrimSpace(reply.Explanation), nil
+}
+
+// parseExplainedReply 解析模型返回的 JSON，容忍代码块包裹和对象前后的多余文字
+func parseExplainedReply(out string) (explainedReply, error) {
+	var reply explainedReply
+	object, ok := jsonObject(out)
+	if !ok {
+		return reply, fmt.Errorf("malformed explanation reply, no JSON object found: %q", out)
+	}
+	if err := json.Unmarshal([]byte(object), &reply); err != nil {
+		return reply, fmt.Errorf("malformed explanation reply %q: %w", out, err)
+	}
+	if strings.TrimSpace(reply.Translation) == "" {
+		return reply, fmt.Errorf("malformed explanation reply, missing translation: %q", out)
+	}
+	return reply, nil
+}
+
+// jsonObject 从模型回复中取出 JSON 对象：去掉包裹的代码块，截取第一个 { 到最后一个 } 之间的内容
+func jsonObject(out string) (string, bool) {
+	s := strings.TrimSpace(out)
+	if m := codeFencePattern.FindStringSubmatch(s); m != nil {
+		s = strings.TrimSpace(m[1])
+	}
+	start, end := strings.Index(s, "{"), strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return s[start : end+1], true
+}
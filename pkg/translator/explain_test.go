@@ -0,0 +1,56 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateWithExplanation(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "```json\n{\"translation\": \"下雨了，倾盆大雨\", \"explanation\": \"'raining cats and dogs' is an idiom for heavy rain.\"}\n```", nil
+	}}
+
+	translation, explanation, err := TranslateWithExplanation(context.Background(), llm, "It's raining cats and dogs", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateWithExplanation() error = %v", err)
+	}
+	if translation != "下雨了，倾盆大雨" {
+		t.Errorf("translation = %q", translation)
+	}
+	if !strings.Contains(explanation, "idiom") {
+		t.Errorf("explanation = %q", explanation)
+	}
+
+	// 只缓存译文
+	if cached, ok := defaultCache.Get("It's raining cats and dogs", "English", "Chinese"); !ok || cached != translation {
+		t.Errorf("cache = %q, %v, want translation", cached, ok)
+	}
+}
+
+func TestTranslateWithExplanation_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{name: "Not JSON", reply: "下雨了"},
+		{name: "Invalid JSON", reply: `{"translation": "下雨了"`},
+		{name: "Missing Translation", reply: `{"explanation": "idiom"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			llm := &fakeLLM{respond: func(prompt string) (string, error) { return tt.reply, nil }}
+
+			_, _, err := TranslateWithExplanation(context.Background(), llm, "It's raining", "English", "Chinese")
+			if err == nil || !strings.Contains(err.Error(), "malformed explanation reply") {
+				t.Errorf("error = %v, want malformed reply error", err)
+			}
+			if _, ok := defaultCache.Get("It's raining", "English", "Chinese"); ok {
+				t.Error("malformed reply should not be cached")
+			}
+		})
+	}
+}
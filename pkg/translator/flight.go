@@ -0,0 +1,59 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// flightTimeout 是合并后共享调用的总超时。共享调用脱离任何一个调用方的 context 运行，
+// 未设置 WithTimeout 时以它为上限，避免没有调用方等待时无限运行
+const flightTimeout = 3 * defaultTimeout
+
+// flightKey 返回合并并发请求使用的键：在缓存键之外计入所有影响译文的配置，只有配置相同的请求才共享一次调用。
+// 设置了无法比较的配置（译后编辑、输出解析器、回调、费用统计）时返回 false，此时不合并
+func (o *options) flightKey(ctx context.Context, key string) (string, bool) {
+	if o.postEdit != nil || o.outputParser != nil || o.callbacks != nil || o.costTracker != nil {
+		return "", false
+	}
+	settings := fmt.Sprintf("%t|%d|%t|%d|%q|%d|%q|%t|%t|%t|%t|%d|%t|%t|%v|%v|%v",
+		o.noCache, o.qualityThreshold, o.strictOutput, o.instructionLanguage, o.outputInstruction,
+		o.maxTokens, o.stopWords, o.normalizeOutput, o.collapseWhitespace, o.unifyQuotes, o.echoGuard, o.echoRetries, o.injectionGuard,
+		o.retryTemperature, o.retryTemperatureStart, o.retryTemperatureStep, o.retryTemperatureMax)
+	model, _ := o.modelName(ctx)
+	return hashKeyParts(key, "flight", model, o.systemPrompt, settings), true
+}
+
+// translateShared 翻译未命中缓存的文本，配置相同的并发请求合并为一次调用。
+// 共享调用在脱离调用方取消的 context 上运行并有自己的超时，每个调用方只按自己的 context 等待结果，
+// 一个调用方超时或取消不会影响其他调用方
+func translateShared(ctx context.Context, llm llms.Model, text string, cacheText string, inputLanguage string, outputLanguage string, key string, o *options) (string, error) {
+	fk, ok := o.flightKey(ctx, key)
+	if !ok {
+		return translateUncached(ctx, llm, text, cacheText, inputLanguage, outputLanguage, o)
+	}
+
+	shared := context.WithoutCancel(ctx)
+	ch := translateGroup.DoChan(fk, func() (any, error) {
+		ctx, cancel := o.sharedContext(shared)
+		defer cancel()
+		return translateUncached(ctx, llm, text, cacheText, inputLanguage, outputLanguage, o)
+	})
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-ch:
+		out, _ := r.Val.(string)
+		return out, r.Err
+	}
+}
+
+// sharedContext 为共享调用派生带总超时的 context：设置了 WithTimeout 时使用它，否则使用 flightTimeout
+func (o *options) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := o.timeout
+	if timeout <= 0 {
+		timeout = flightTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
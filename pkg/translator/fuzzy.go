@@ -0,0 +1,136 @@
+package translator
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+)
+
+// fuzzyScanLimit 是模糊匹配最多扫描的最近缓存条目数，保证未命中时的开销有上限
+const fuzzyScanLimit = 256
+
+// SimilarityFunc 返回两段文本的相似度，范围 [0, 1]，1 表示相同
+type SimilarityFunc func(a, b string) float64
+
+// WithFuzzyMatch 开启模糊缓存匹配：精确匹配未命中时，在最近写入的缓存条目中
+// 查找相似度不低于 threshold 的原文（如仅末尾标点不同），命中则直接返回其译文。
+// 只扫描最近的 256 个条目；默认按归一化编辑距离计算相似度，可用 WithSimilarity 替换
+func WithFuzzyMatch(threshold float64) Option {
+	return func(o *options) {
+		o.fuzzyThreshold = threshold
+	}
+}
+
+// WithSimilarity 设置模糊缓存匹配使用的相似度函数，需与 WithFuzzyMatch 一起使用
+func WithSimilarity(fn SimilarityFunc) Option {
+	return func(o *options) {
+		o.similarity = fn
+	}
+}
+
+// LevenshteinSimilarity 按字符计算归一化编辑距离相似度：1 - 编辑距离 / 较长文本的长度
+func LevenshteinSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein 计算两个字符序列的编辑距离，只保留两行状态
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// fuzzyGet 按配置在缓存中模糊查找译文，未开启模糊匹配时直接返回未命中
+func (o *options) fuzzyGet(ctx context.Context, c *TranslationCache, text, inputLang, outputLang string) (string, bool) {
+	// 模糊匹配的候选条目不区分提示、命名空间和模型，带有它们的请求只使用精确匹配
+	if o.fuzzyThreshold <= 0 || !o.plainCacheKey(ctx) {
+		return "", false
+	}
+	similarity := o.similarity
+	if similarity == nil {
+		similarity = LevenshteinSimilarity
+	}
+	result, source, ok := c.getFuzzy(o.normalizeInput(text), inputLang, outputLang, o.fuzzyThreshold, similarity)
+	if ok {
+		log.Printf("Fuzzy cache hit for text: %s (matched %s)", text, source)
+	}
+	return result, ok
+}
+
+// getFuzzy 从新到旧扫描最近写入的条目，返回同一语言对中与 text 最相似且不低于 threshold 的译文及其原文
+func (c *TranslationCache) getFuzzy(text, inputLang, outputLang string, threshold float64, similarity SimilarityFunc) (string, string, bool) {
+	if c.disabled.Load() {
+		return "", "", false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock()
+	var best cacheEntry
+	bestScore := -1.0
+	for i := 1; i <= len(c.recent); i++ {
+		key := c.recent[(c.recentNext-i+len(c.recent))%len(c.recent)]
+		entry, ok := c.cache[key]
+		if !ok || now.Sub(entry.timestamp) >= c.ttl {
+			continue
+		}
+		if !strings.EqualFold(entry.inputLang, inputLang) || !strings.EqualFold(entry.outputLang, outputLang) {
+			continue
+		}
+		if score := similarity(text, entry.source); score >= threshold && score > bestScore {
+			best, bestScore = entry, score
+		}
+	}
+	if bestScore < 0 {
+		return "", "", false
+	}
+	return best.result, best.source, true
+}
+
+// recordRecent 把 key 记入最近写入的环形缓冲，已满时覆盖最旧的键；
+// key 已在缓冲中时把它移到最新的位置，不重复占用扫描名额。调用方需持有写锁
+func (c *TranslationCache) recordRecent(key string) {
+	for _, k := range c.recent {
+		if k != key {
+			continue
+		}
+		// 按从旧到新的顺序重排，去掉旧位置后追加到末尾；未满时 recentNext 等于长度，前半段为空
+		ordered := make([]string, 0, len(c.recent))
+		for _, k := range slices.Concat(c.recent[c.recentNext:], c.recent[:c.recentNext]) {
+			if k != key {
+				ordered = append(ordered, k)
+			}
+		}
+		c.recent = append(ordered, key)
+		c.recentNext = len(c.recent) % fuzzyScanLimit
+		return
+	}
+	if len(c.recent) < fuzzyScanLimit {
+		c.recent = append(c.recent, key)
+		c.recentNext = len(c.recent) % fuzzyScanLimit
+		return
+	}
+	c.recent[c.recentNext] = key
+	c.recentNext = (c.recentNext + 1) % fuzzyScanLimit
+}
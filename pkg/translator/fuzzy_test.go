@@ -0,0 +1,154 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "", b: "", want: 1},
+		{a: "hello", b: "hello", want: 1},
+		{a: "Hello world", b: "Hello world!", want: 1 - 1.0/12},
+		{a: "kitten", b: "sitting", want: 1 - 3.0/7},
+		{a: "你好世界", b: "你好世界。", want: 0.8},
+		{a: "abc", b: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := LevenshteinSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("LevenshteinSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate_FuzzyMatch(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world":      "你好，世界",
+		"Goodbye everyone": "大家再见",
+	})
+
+	if _, err := Translate(ctx, llm, "Hello world", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	// 仅末尾标点不同：模糊命中，不调用模型
+	result, err := Translate(ctx, llm, "Hello world!", "English", "Chinese", WithFuzzyMatch(0.9))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好，世界" {
+		t.Errorf("Translate() = %q, want fuzzy hit %q", result, "你好，世界")
+	}
+	if n := llm.Calls(); n != 1 {
+		t.Errorf("LLM called %d times, want 1", n)
+	}
+
+	// 明显不同的文本不命中
+	result, err = Translate(ctx, llm, "Goodbye everyone", "English", "Chinese", WithFuzzyMatch(0.9))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "大家再见" {
+		t.Errorf("Translate() = %q, want %q", result, "大家再见")
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+
+	// 不同的语言对不命中
+	if _, ok := (&options{fuzzyThreshold: 0.9}).fuzzyGet(context.Background(), defaultCache, "Hello world!", "English", "Japanese"); ok {
+		t.Error("fuzzy match crossed language pairs")
+	}
+
+	// 未开启时不做模糊匹配
+	if _, ok := newOptions(nil).fuzzyGet(context.Background(), defaultCache, "Hello world!", "English", "Chinese"); ok {
+		t.Error("fuzzy match should be opt-in")
+	}
+}
+
+func TestTranslate_FuzzyMatchCustomSimilarity(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+
+	// 自定义相似度：长度相同即视为相同
+	sameLength := func(a, b string) float64 {
+		if len(a) == len(b) {
+			return 1
+		}
+		return 0
+	}
+	llm := newDictLLM(map[string]string{"World": "世界"})
+	result, err := Translate(context.Background(), llm, "World", "English", "Chinese",
+		WithFuzzyMatch(0.5), WithSimilarity(sameLength))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" || llm.Calls() != 0 {
+		t.Errorf("Translate() = %q after %d calls, want custom similarity hit", result, llm.Calls())
+	}
+}
+
+func TestTranslationCache_FuzzyScanIsBounded(t *testing.T) {
+	c := NewTranslationCache()
+	c.Set("Hello world", "English", "Chinese", "你好，世界")
+	// 写入足够多的新条目，把最早的条目挤出扫描范围
+	for i := 0; i < fuzzyScanLimit; i++ {
+		c.Set(fmt.Sprintf("filler %d", i), "English", "Chinese", "填充")
+	}
+
+	if len(c.recent) != fuzzyScanLimit {
+		t.Errorf("recent keys = %d, want %d", len(c.recent), fuzzyScanLimit)
+	}
+	if _, _, ok := c.getFuzzy("Hello world!", "English", "Chinese", 0.9, LevenshteinSimilarity); ok {
+		t.Error("fuzzy match found an entry outside the scan limit")
+	}
+	// 精确匹配不受影响
+	if got, ok := c.Get("Hello world", "English", "Chinese"); !ok || got != "你好，世界" {
+		t.Errorf("Get() = %q, %v, want exact hit", got, ok)
+	}
+}
+
+func TestTranslationCache_RecentKeysDeduplicated(t *testing.T) {
+	c := NewTranslationCache()
+	c.Set("Goodbye world", "English", "Chinese", "再见，世界")
+	// 反复写入同一个键不应把其他条目挤出扫描范围
+	for i := 0; i < fuzzyScanLimit; i++ {
+		c.Set("Hello world", "English", "Chinese", "你好，世界")
+	}
+
+	if len(c.recent) != 2 {
+		t.Errorf("recent keys = %d, want 2", len(c.recent))
+	}
+	if got, _, ok := c.getFuzzy("Goodbye world!", "English", "Chinese", 0.9, LevenshteinSimilarity); !ok || got != "再见，世界" {
+		t.Errorf("getFuzzy() = %q, %v, want hit on the older entry", got, ok)
+	}
+}
+
+func TestTranslationCache_RecentKeyMovesToNewest(t *testing.T) {
+	c := NewTranslationCache()
+	c.Set("Hello world", "English", "Chinese", "你好，世界")
+	for i := 0; i < fuzzyScanLimit-1; i++ {
+		c.Set(fmt.Sprintf("filler %d", i), "English", "Chinese", "填充")
+	}
+	// 缓冲已满时重写最旧的键，它应成为最新的键，下一次写入挤出的是 filler 0
+	c.Set("Hello world", "English", "Chinese", "你好，世界")
+	c.Set("one more", "English", "Chinese", "再来一条")
+
+	if len(c.recent) != fuzzyScanLimit {
+		t.Errorf("recent keys = %d, want %d", len(c.recent), fuzzyScanLimit)
+	}
+	if _, _, ok := c.getFuzzy("Hello world!", "English", "Chinese", 0.9, LevenshteinSimilarity); !ok {
+		t.Error("rewritten key was evicted from the scan range")
+	}
+	if _, _, ok := c.getFuzzy("filler 0", "English", "Chinese", 1, LevenshteinSimilarity); ok {
+		t.Error("oldest filler is still in the scan range")
+	}
+}
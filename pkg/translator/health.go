@@ -0,0 +1,39 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// 健康检查使用的固定翻译和超时
+const (
+	healthCheckText    = "OK"
+	healthCheckInput   = "English"
+	healthCheckOutput  = "Chinese"
+	healthCheckTimeout = 10 * time.Second
+)
+
+// HealthCheck 用一次很短的固定翻译检查模型是否可达且输出正常，适合用作就绪探针。
+// 结果为空或与原文相同都视为不健康；检查不读写缓存。失败时返回的错误包装了 ErrUnhealthy
+func HealthCheck(ctx context.Context, llm llms.Model) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	out, err := translateOnce(ctx, llm, healthCheckText, healthCheckInput, healthCheckOutput, newOptions(nil))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnhealthy, err)
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return fmt.Errorf("%w: empty translation", ErrUnhealthy)
+	}
+	if strings.EqualFold(out, healthCheckText) {
+		return fmt.Errorf("%w: translation identical to input %q", ErrUnhealthy, out)
+	}
+	return nil
+}
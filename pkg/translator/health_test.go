@@ -0,0 +1,41 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		err     error
+		healthy bool
+	}{
+		{name: "Healthy", reply: "好的", healthy: true},
+		{name: "Provider Error", err: errors.New("connection refused")},
+		{name: "Empty Output", reply: "  "},
+		{name: "Echoed Input", reply: "ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			llm := &fakeLLM{respond: func(prompt string) (string, error) {
+				return tt.reply, tt.err
+			}}
+
+			err := HealthCheck(context.Background(), llm)
+			if tt.healthy && err != nil {
+				t.Errorf("HealthCheck() error = %v, want nil", err)
+			}
+			if !tt.healthy && !errors.Is(err, ErrUnhealthy) {
+				t.Errorf("HealthCheck() error = %v, want ErrUnhealthy", err)
+			}
+			if _, ok := defaultCache.Get(healthCheckText, healthCheckInput, healthCheckOutput); ok {
+				t.Error("health check must not write to the cache")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package translator
+
+// hintCacheTag 用于区分带提示和不带提示的缓存键
+const hintCacheTag = "hint"
+
+// WithHint 为有歧义的词语提供简短的提示，例如 "'bank' means financial institution here"。
+// 提示作为指导附加在翻译提示词之后，并参与缓存键的计算，不同提示的译文分别缓存。
+// 与术语表相比，提示只针对单次请求
+func WithHint(s string) Option {
+	return func(o *options) {
+		o.hint = s
+	}
+}
+
+// withHint 在设置了提示时把它附加到翻译模板之后，并写入模板变量
+func (o *options) withHint(template string, values map[string]any) string {
+	if o.hint == "" {
+		return template
+	}
+	values["hint"] = o.hint
+	return template + "\nHint: {{.hint}}"
+}
@@ -0,0 +1,55 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslate_WithHint(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+	hint := "'bank' means financial institution here"
+
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "financial institution") {
+			return "银行", nil
+		}
+		return "河岸", nil
+	}}
+
+	result, err := Translate(ctx, llm, "bank", "English", "Chinese", WithHint(hint))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "银行" {
+		t.Errorf("Translate() = %q, want %q", result, "银行")
+	}
+	if !strings.Contains(llm.prompts[0], "Hint: "+hint) {
+		t.Errorf("prompt does not contain the hint:\n%s", llm.prompts[0])
+	}
+
+	// 不带提示的请求使用不同的缓存键，不会命中带提示的结果
+	result, err = Translate(ctx, llm, "bank", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "河岸" {
+		t.Errorf("Translate() without hint = %q, want %q", result, "河岸")
+	}
+	if strings.Contains(llm.prompts[1], "Hint:") {
+		t.Errorf("prompt without hint contains a hint:\n%s", llm.prompts[1])
+	}
+
+	// 相同的提示命中缓存
+	if _, err := Translate(ctx, llm, "bank", "English", "Chinese", WithHint(hint)); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+
+	if got, want := newOptions([]Option{WithHint(hint)}).cacheKey(context.Background(), "bank", "English", "Chinese"), getCacheKey("bank", "English", "Chinese"); got == want {
+		t.Error("hint does not change the cache key")
+	}
+}
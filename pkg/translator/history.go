@@ -0,0 +1,87 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// historyCacheTag 用于区分带对话历史和不带对话历史的缓存键
+const historyCacheTag = "history"
+
+// WithHistoryInCacheKey 让对话历史参与缓存键的计算。
+// 默认情况下同一句话无论上下文如何都共享一条缓存。
+func WithHistoryInCacheKey() Option {
+	return func(o *options) {
+		o.historyInCacheKey = true
+	}
+}
+
+// TranslateWithHistory 结合对话历史翻译当前这句话，使代词和术语与前文保持一致。
+// history 按 [原文1, 译文1, 原文2, 译文2, ...] 的顺序给出之前的对话轮次。
+func TranslateWithHistory(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, history []string, opts ...Option) (string, error) {
+	if len(history) == 0 {
+		return Translate(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+	}
+
+	// 验证输入
+	if text == "" {
+		return "", ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return "", ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return "", ErrEmptyOutputLanguage
+	}
+	if len(history)%2 != 0 {
+		return "", fmt.Errorf("history must contain source/translation pairs, got %d items", len(history))
+	}
+
+	o := newOptions(opts)
+	// 与 Translate 使用同一个缓存键（含提示、命名空间和模型），开启 WithHistoryInCacheKey 时再加上历史
+	key := o.cacheKey(ctx, o.cacheNormalization.apply(text), inputLanguage, outputLanguage)
+	if o.historyInCacheKey {
+		key = hashKeyParts(append([]string{key, historyCacheTag}, history...)...)
+	}
+
+	// 检查缓存
+	if result, ok := o.translationCache().getKey(key); ok {
+		log.Printf("Cache hit for text: %s", text)
+		return result, nil
+	}
+
+	var turns strings.Builder
+	for i := 0; i < len(history); i += 2 {
+		fmt.Fprintf(&turns, "Source: %s\nTranslation: %s\n", history[i], history[i+1])
+	}
+
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"history":        strings.TrimRight(turns.String(), "\n"),
+		"text":           text,
+	}
+	out, err := runPrompt(ctx, llm, o, o.withHint(o.withOutputInstruction(
+		`The following is an ongoing conversation translated from {{.inputLanguage}} to {{.outputLanguage}}. Keep pronouns and terminology consistent with the previous turns.
+Previous turns:
+{{.history}}
+Translate "{{.text}}" from {{.inputLanguage}} to {{.outputLanguage}}. `+defaultOutputInstruction, values), values), values)
+	if err != nil {
+		log.Printf("OpenAI API 调用失败（状态码 %d），详细错误信息: %v", StatusCode(err), err)
+		return "", fmt.Errorf("translation failed: %w", err)
+	}
+
+	out, err = o.parse(out)
+	if err != nil {
+		return "", err
+	}
+	out = o.postEditText(out, inputLanguage, outputLanguage)
+
+	// 缓存结果
+	o.translationCache().setKey(key, out)
+	return out, nil
+}
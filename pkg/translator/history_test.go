@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateWithHistory(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"She said yes.": "她答应了。"})
+
+	history := []string{
+		"Where is Alice?", "爱丽丝在哪里？",
+		"Did you ask her?", "你问她了吗？",
+	}
+	result, err := TranslateWithHistory(context.Background(), llm, "She said yes.", "English", "Chinese", history)
+	if err != nil {
+		t.Fatalf("TranslateWithHistory() error = %v", err)
+	}
+	if result != "她答应了。" {
+		t.Errorf("TranslateWithHistory() = %q, want %q", result, "她答应了。")
+	}
+
+	prompt := llm.prompts[0]
+	for _, turn := range history {
+		if !strings.Contains(prompt, turn) {
+			t.Errorf("prompt missing history turn %q:\n%s", turn, prompt)
+		}
+	}
+}
+
+func TestTranslateWithHistory_CacheKey(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"She said yes.": "她答应了。"})
+	ctx := context.Background()
+
+	// 默认情况下历史不影响缓存键，因此与普通翻译共享缓存
+	if _, err := TranslateWithHistory(ctx, llm, "She said yes.", "English", "Chinese", []string{"Hi", "嗨"}); err != nil {
+		t.Fatalf("TranslateWithHistory() error = %v", err)
+	}
+	if result, ok := defaultCache.Get("She said yes.", "English", "Chinese"); !ok || result != "她答应了。" {
+		t.Errorf("expected history translation to be cached under plain key, got %q, %v", result, ok)
+	}
+
+	// 启用后，不同的历史使用不同的缓存条目
+	defaultCache.Clear()
+	opt := WithHistoryInCacheKey()
+	if _, err := TranslateWithHistory(ctx, llm, "She said yes.", "English", "Chinese", []string{"Hi", "嗨"}, opt); err != nil {
+		t.Fatalf("TranslateWithHistory() error = %v", err)
+	}
+	if _, ok := defaultCache.Get("She said yes.", "English", "Chinese"); ok {
+		t.Error("history-keyed translation should not be cached under plain key")
+	}
+	if _, err := TranslateWithHistory(ctx, llm, "She said yes.", "English", "Chinese", []string{"Bye", "再见"}, opt); err != nil {
+		t.Fatalf("TranslateWithHistory() error = %v", err)
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("expected different histories to miss the cache, got %d LLM calls", llm.Calls())
+	}
+}
+
+func TestTranslateWithHistory_ModelCacheKey(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"She said yes.": "她答应了。"})
+	ctx := context.Background()
+	history := []string{"Hi", "嗨"}
+
+	calls := []struct {
+		ctx  context.Context
+		opts []Option
+	}{
+		{ctx, nil},
+		{ctx, []Option{WithModel("gpt-4o")}},
+		{WithModelOverride(ctx, "cheap-model"), nil},
+		{ctx, []Option{WithModel("gpt-4o")}},                          // 与第二次相同，命中缓存
+		{ctx, []Option{WithModel("gpt-4o"), WithHistoryInCacheKey()}}, // 历史参与计算时同样区分模型
+		{ctx, []Option{WithModel("cheap-model"), WithHistoryInCacheKey()}},
+	}
+	for _, c := range calls {
+		if _, err := TranslateWithHistory(c.ctx, llm, "She said yes.", "English", "Chinese", history, c.opts...); err != nil {
+			t.Fatalf("TranslateWithHistory() error = %v", err)
+		}
+	}
+	if llm.Calls() != 5 {
+		t.Errorf("LLM called %d times, want 5 (one per model and cache key mode)", llm.Calls())
+	}
+
+	// 与 Translate 共用同一个按模型区分的缓存条目
+	if _, err := Translate(ctx, llm, "She said yes.", "English", "Chinese", WithModel("gpt-4o")); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if llm.Calls() != 5 {
+		t.Errorf("Translate() with the same model missed the cache, %d LLM calls", llm.Calls())
+	}
+}
+
+func TestTranslateWithHistory_OddHistory(t *testing.T) {
+	llm := newDictLLM(nil)
+	_, err := TranslateWithHistory(context.Background(), llm, "Hi", "English", "Chinese", []string{"only source"})
+	if err == nil {
+		t.Error("expected error for unpaired history")
+	}
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// TranslateHTML 翻译 HTML 中的文本节点，保留标签、属性和文档结构。
+// <script> 和 <style> 中的内容不会被翻译；文本前后的空白原样保留。
+func TranslateHTML(ctx context.Context, llm llms.Model, htmlText string, inputLanguage string, outputLanguage string) (string, error) {
+	if strings.TrimSpace(htmlText) == "" {
+		return "", ErrEmptyText
+	}
+
+	// 完整文档按文档解析，片段则在 <body> 上下文中解析，避免补全多余的标签
+	var nodes []*html.Node
+	if strings.Contains(strings.ToLower(htmlText), "<html") {
+		doc, err := html.Parse(strings.NewReader(htmlText))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		nodes = []*html.Node{doc}
+	} else {
+		body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		fragment, err := html.ParseFragment(strings.NewReader(htmlText), body)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		nodes = fragment
+	}
+
+	for _, n := range nodes {
+		if err := translateHTMLNode(ctx, llm, n, inputLanguage, outputLanguage); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		if err := html.Render(&b, n); err != nil {
+			return "", fmt.Errorf("failed to render HTML: %w", err)
+		}
+	}
+	return b.String(), nil
+}
+
+// translateHTMLNode 递归翻译节点下的所有文本节点
+func translateHTMLNode(ctx context.Context, llm llms.Model, n *html.Node, inputLanguage string, outputLanguage string) error {
+	if n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style) {
+		return nil
+	}
+
+	if n.Type == html.TextNode {
+		trimmed := strings.TrimSpace(n.Data)
+		if trimmed == "" {
+			return nil
+		}
+		translated, err := Translate(ctx, llm, trimmed, inputLanguage, outputLanguage)
+		if err != nil {
+			return fmt.Errorf("failed to translate text node %q: %w", trimmed, err)
+		}
+		start := strings.Index(n.Data, trimmed)
+		n.Data = n.Data[:start] + translated + n.Data[start+len(trimmed):]
+		return nil
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := translateHTMLNode(ctx, llm, c, inputLanguage, outputLanguage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateHTML(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world":   "你好，世界",
+		"Click here":    "点击这里",
+		"Tom & Jerry":   "汤姆和杰瑞",
+		"Nested text":   "嵌套文本",
+		"Page title":    "页面标题",
+		"Body greeting": "正文问候",
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Nested Markup",
+			input: `<div class="greeting"><p>Hello world</p><a href="/docs?a=1&amp;b=2" title="link">Click here</a></div>`,
+			want:  `<div class="greeting"><p>你好，世界</p><a href="/docs?a=1&amp;b=2" title="link">点击这里</a></div>`,
+		},
+		{
+			name:  "Whitespace Preserved",
+			input: "<ul>\n  <li> <b>Nested text</b> </li>\n</ul>",
+			want:  "<ul>\n  <li> <b>嵌套文本</b> </li>\n</ul>",
+		},
+		{
+			name:  "Entities Round Trip",
+			input: `<span>Tom &amp; Jerry</span>`,
+			want:  `<span>汤姆和杰瑞</span>`,
+		},
+		{
+			name:  "Script And Style Skipped",
+			input: `<style>p { color: red; }</style><script>var s = "Hello world";</script><p>Hello world</p>`,
+			want:  `<style>p { color: red; }</style><script>var s = "Hello world";</script><p>你好，世界</p>`,
+		},
+		{
+			name:  "Full Document",
+			input: `<html><head><title>Page title</title></head><body><p>Body greeting</p></body></html>`,
+			want:  `<html><head><title>页面标题</title></head><body><p>正文问候</p></body></html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TranslateHTML(context.Background(), llm, tt.input, "English", "Chinese")
+			if err != nil {
+				t.Fatalf("TranslateHTML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslateHTML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateHTML_EscapesTranslatedText(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Terms": "条款 <与> 条件"})
+
+	got, err := TranslateHTML(context.Background(), llm, "<p>Terms</p>", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateHTML() error = %v", err)
+	}
+	if !strings.Contains(got, "&lt;与&gt;") {
+		t.Errorf("translated text should be escaped, got %s", got)
+	}
+}
@@ -0,0 +1,103 @@
+package translator
+
+import "strings"
+
+// defaultOutputInstruction 是翻译模板中要求模型只输出译文的英文指令
+const defaultOutputInstruction = "Output the translation only, no explanations."
+
+// InstructionLanguage 指定 "只输出译文" 指令使用的语言
+type InstructionLanguage int
+
+const (
+	// InstructionEnglish 使用英文指令（默认）
+	InstructionEnglish InstructionLanguage = iota
+	// InstructionSource 使用源语言书写指令
+	InstructionSource
+	// InstructionTarget 使用目标语言书写指令
+	InstructionTarget
+)
+
+// outputInstructions 是 "只输出译文" 指令的各语言版本，键为小写的语言名称或 BCP-47 标签
+var outputInstructions = map[string]string{
+	"chinese":             "只输出译文，不要任何解释。",
+	"simplified chinese":  "只输出译文，不要任何解释。",
+	"zh":                  "只输出译文，不要任何解释。",
+	"zh-cn":               "只输出译文，不要任何解释。",
+	"traditional chinese": "只輸出譯文，不要任何解釋。",
+	"zh-tw":               "只輸出譯文，不要任何解釋。",
+	"zh-hk":               "只輸出譯文，不要任何解釋。",
+	"japanese":            "訳文のみを出力し、説明は付けないでください。",
+	"ja":                  "訳文のみを出力し、説明は付けないでください。",
+	"korean":              "번역문만 출력하고 설명은 덧붙이지 마세요.",
+	"ko":                  "번역문만 출력하고 설명은 덧붙이지 마세요.",
+	"french":              "Donne uniquement la traduction, sans explications.",
+	"fr":                  "Donne uniquement la traduction, sans explications.",
+	"german":              "Gib nur die Übersetzung aus, ohne Erklärungen.",
+	"de":                  "Gib nur die Übersetzung aus, ohne Erklärungen.",
+	"spanish":             "Devuelve solo la traducción, sin explicaciones.",
+	"es":                  "Devuelve solo la traducción, sin explicaciones.",
+	"portuguese":          "Forneça apenas a tradução, sem explicações.",
+	"pt":                  "Forneça apenas a tradução, sem explicações.",
+	"italian":             "Restituisci solo la traduzione, senza spiegazioni.",
+	"it":                  "Restituisci solo la traduzione, senza spiegazioni.",
+	"russian":             "Выведи только перевод, без пояснений.",
+	"ru":                  "Выведи только перевод, без пояснений.",
+}
+
+// WithInstructionLanguage 设置翻译提示词中 "只输出译文" 指令的语言。在两种非英语语言之间翻译时，
+// 一些较小的模型更容易遵守用源语言或目标语言书写的指令。没有内置版本的语言仍使用英文指令
+func WithInstructionLanguage(l InstructionLanguage) Option {
+	return func(o *options) {
+		o.instructionLanguage = l
+	}
+}
+
+// WithOutputInstruction 用自定义文本替换翻译提示词中的 "只输出译文" 指令，优先于 WithInstructionLanguage
+func WithOutputInstruction(s string) Option {
+	return func(o *options) {
+		o.outputInstruction = s
+	}
+}
+
+// outputInstructionFor 返回 lang 对应的内置指令，依次按语言名称、规范化的 BCP-47 标签和主语言子标签查找
+func outputInstructionFor(lang string) (string, bool) {
+	if s, ok := outputInstructions[strings.ToLower(strings.TrimSpace(lang))]; ok {
+		return s, true
+	}
+	if !isLocaleTag(lang) {
+		return "", false
+	}
+	tag := strings.ToLower(canonicalLocale(lang))
+	if s, ok := outputInstructions[tag]; ok {
+		return s, true
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	s, ok := outputInstructions[primary]
+	return s, ok
+}
+
+// withOutputInstruction 按配置替换模板中的英文 "只输出译文" 指令。替换后的指令作为模板变量传入，
+// 自定义文本中的 {{ 不会被当作模板语法；模板中没有该指令（如自定义的注册模板）时原样返回
+func (o *options) withOutputInstruction(template string, values map[string]any) string {
+	instruction := o.outputInstruction
+	if instruction == "" {
+		var lang string
+		switch o.instructionLanguage {
+		case InstructionSource:
+			lang, _ = values["inputLanguage"].(string)
+		case InstructionTarget:
+			lang, _ = values["outputLanguage"].(string)
+		default:
+			return template
+		}
+		var ok bool
+		if instruction, ok = outputInstructionFor(lang); !ok {
+			return template
+		}
+	}
+	if !strings.Contains(template, defaultOutputInstruction) {
+		return template
+	}
+	values["outputInstruction"] = instruction
+	return strings.ReplaceAll(template, defaultOutputInstruction, "{{.outputInstruction}}")
+}
@@ -0,0 +1,57 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslate_OutputInstruction(t *testing.T) {
+	llm := newDictLLM(map[string]string{"こんにちは": "你好"})
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default English", nil, defaultOutputInstruction},
+		{"source language", []Option{WithInstructionLanguage(InstructionSource)}, "訳文のみを出力し、説明は付けないでください。"},
+		{"target language", []Option{WithInstructionLanguage(InstructionTarget)}, "只输出译文，不要任何解释。"},
+		{"custom", []Option{WithOutputInstruction("仅回复译文 {{不要模板}}")}, "仅回复译文 {{不要模板}}"},
+		{"custom wins", []Option{WithInstructionLanguage(InstructionTarget), WithOutputInstruction("Just the translation.")}, "Just the translation."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			if _, err := Translate(context.Background(), llm, "こんにちは", "Japanese", "Chinese", tt.opts...); err != nil {
+				t.Fatalf("Translate() error = %v", err)
+			}
+			prompt := llm.prompts[len(llm.prompts)-1]
+			if !strings.Contains(prompt, tt.want) {
+				t.Errorf("prompt %q does not contain instruction %q", prompt, tt.want)
+			}
+			if tt.want != defaultOutputInstruction && strings.Contains(prompt, defaultOutputInstruction) {
+				t.Errorf("prompt still contains the English instruction: %q", prompt)
+			}
+		})
+	}
+}
+
+func TestOutputInstructionFor(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+		ok   bool
+	}{
+		{"French", "Donne uniquement la traduction, sans explications.", true},
+		{"zh_TW", "只輸出譯文，不要任何解釋。", true},
+		{"fr-CA", "Donne uniquement la traduction, sans explications.", true},
+		{"Klingon", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := outputInstructionFor(tt.lang)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("outputInstructionFor(%q) = %q, %v, want %q, %v", tt.lang, got, ok, tt.want, tt.ok)
+		}
+	}
+}
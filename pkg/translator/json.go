@@ -0,0 +1,82 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// urlValuePattern 匹配整个值就是 URL 的字符串，这类值不翻译
+var urlValuePattern = regexp.MustCompile(`^\s*[a-zA-Z][a-zA-Z0-9+.-]*://\S+\s*$`)
+
+// WithSkipKeys 设置 JSON 翻译时要跳过的键名模式，键名匹配的值保持原样
+func WithSkipKeys(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		o.skipKeys = pattern
+	}
+}
+
+// TranslateJSONValues 翻译 JSON 中的字符串值，保留键名、结构以及数字、布尔和 null。
+// 值为 URL 的字符串不会被翻译；输出为紧凑格式，对象的键按字母顺序排列。
+func TranslateJSONValues(ctx context.Context, llm llms.Model, raw []byte, inputLanguage string, outputLanguage string, opts ...Option) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber() // 保留数字的原始精度
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	o := newOptions(opts)
+	translated, err := translateJSONValue(ctx, llm, value, "", inputLanguage, outputLanguage, o, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(translated); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// translateJSONValue 递归翻译 JSON 值中的字符串叶子节点，key 为该值所在的对象键名
+func translateJSONValue(ctx context.Context, llm llms.Model, value any, key string, inputLanguage string, outputLanguage string, o *options, opts []Option) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			translated, err := translateJSONValue(ctx, llm, child, k, inputLanguage, outputLanguage, o, opts)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = translated
+		}
+		return v, nil
+	case []any:
+		for i, child := range v {
+			translated, err := translateJSONValue(ctx, llm, child, key, inputLanguage, outputLanguage, o, opts)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = translated
+		}
+		return v, nil
+	case string:
+		if v == "" || urlValuePattern.MatchString(v) || (o.skipKeys != nil && key != "" && o.skipKeys.MatchString(key)) {
+			return v, nil
+		}
+		translated, err := Translate(ctx, llm, v, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate value of key %q: %w", key, err)
+		}
+		return translated, nil
+	default:
+		// 数字、布尔和 null 原样返回
+		return v, nil
+	}
+}
@@ -0,0 +1,68 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestTranslateJSONValues(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello":    "你好",
+		"Save":     "保存",
+		"Cancel":   "取消",
+		"Settings": "设置",
+	})
+
+	raw := []byte(`{
+		"title": "Hello",
+		"buttons": ["Save", "Cancel"],
+		"menu": {"label": "Settings", "order": 3, "enabled": true, "icon": null},
+		"homepage": "https://example.com",
+		"id": 12345678901234567890
+	}`)
+
+	got, err := TranslateJSONValues(context.Background(), llm, raw, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateJSONValues() error = %v", err)
+	}
+
+	want := `{"buttons":["保存","取消"],"homepage":"https://example.com","id":12345678901234567890,"menu":{"enabled":true,"icon":null,"label":"设置","order":3},"title":"你好"}`
+	if string(got) != want {
+		t.Errorf("TranslateJSONValues() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTranslateJSONValues_SkipKeys(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	raw := []byte(`{"title": "Hello", "route_id": "Hello", "items": [{"route_name": "Hello"}]}`)
+	got, err := TranslateJSONValues(context.Background(), llm, raw, "English", "Chinese", WithSkipKeys(regexp.MustCompile(`^route_`)))
+	if err != nil {
+		t.Fatalf("TranslateJSONValues() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	want := map[string]any{
+		"title":    "你好",
+		"route_id": "Hello",
+		"items":    []any{map[string]any{"route_name": "Hello"}},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("TranslateJSONValues() = %v, want %v", decoded, want)
+	}
+}
+
+func TestTranslateJSONValues_InvalidJSON(t *testing.T) {
+	llm := newDictLLM(nil)
+	if _, err := TranslateJSONValues(context.Background(), llm, []byte(`{"title":`), "English", "Chinese"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,130 @@
+package translator
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// globalLimiter 限制整个进程中同时进行的 LLM 调用数，为 nil 时不限制
+var globalLimiter struct {
+	mu  sync.RWMutex
+	sem chan struct{}
+}
+
+// SetGlobalConcurrency 设置所有翻译调用共享的最大并发 LLM 请求数。
+// 每次批量、预热、目录翻译仍各自限制并发，但多个调用同时进行时，
+// 它们发出的 LLM 请求总数不会超过 n。n <= 0 表示不限制（默认）。
+// 修改只影响之后开始等待的请求，已在进行中的请求在原限额内完成
+func SetGlobalConcurrency(n int) {
+	globalLimiter.mu.Lock()
+	defer globalLimiter.mu.Unlock()
+	if n <= 0 {
+		globalLimiter.sem = nil
+		return
+	}
+	globalLimiter.sem = make(chan struct{}, n)
+}
+
+// acquireGlobal 等待全局并发额度，返回释放额度的函数；ctx 结束时放弃等待
+func acquireGlobal(ctx context.Context) (func(), error) {
+	globalLimiter.mu.RLock()
+	sem := globalLimiter.sem
+	globalLimiter.mu.RUnlock()
+	if sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// RateLimiter 控制发往模型的请求速率：Wait 阻塞到允许发出下一次请求，ctx 结束时返回其错误。
+// golang.org/x/time/rate.Limiter 满足该接口，也可以使用 NewIntervalLimiter
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// rateLimiters 保存按语言对配置的限流器，未配置的语言对使用 fallback，fallback 为 nil 时不限流
+var rateLimiters = struct {
+	mu       sync.RWMutex
+	pairs    map[string]RateLimiter
+	fallback RateLimiter
+}{pairs: make(map[string]RateLimiter)}
+
+// SetRateLimiter 为指定语言对设置限流器，Translate 每次未命中缓存、需要调用模型前都会等待它。
+// 适合不同语言对由不同提供方或端点承担、各自有调用频率限制的部署。
+// 语言名称与 RegisterPrompt 一样不区分大小写和首尾空白；l 为 nil 时删除该语言对的配置
+func SetRateLimiter(inputLang, outputLang string, l RateLimiter) {
+	key := languagePairKey(inputLang, outputLang)
+
+	rateLimiters.mu.Lock()
+	defer rateLimiters.mu.Unlock()
+	if l == nil {
+		delete(rateLimiters.pairs, key)
+		return
+	}
+	rateLimiters.pairs[key] = l
+}
+
+// SetDefaultRateLimiter 设置未单独配置的语言对使用的限流器，nil 表示不限流（默认）
+func SetDefaultRateLimiter(l RateLimiter) {
+	rateLimiters.mu.Lock()
+	defer rateLimiters.mu.Unlock()
+	rateLimiters.fallback = l
+}
+
+// waitRateLimit 等待语言对对应的限流器放行，没有配置限流器时立即返回
+func waitRateLimit(ctx context.Context, inputLang, outputLang string) error {
+	rateLimiters.mu.RLock()
+	l, ok := rateLimiters.pairs[languagePairKey(inputLang, outputLang)]
+	if !ok {
+		l = rateLimiters.fallback
+	}
+	rateLimiters.mu.RUnlock()
+	if l == nil {
+		return nil
+	}
+	return l.Wait(ctx)
+}
+
+// intervalLimiter 让相邻两次放行至少间隔 interval
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // 下一次可以放行的时间
+}
+
+// NewIntervalLimiter 创建一个每 interval 最多放行一次请求的限流器，不允许突发
+func NewIntervalLimiter(interval time.Duration) RateLimiter {
+	return &intervalLimiter{interval: interval}
+}
+
+// Wait 预约下一个放行时间并等待到该时间；ctx 先结束时返回其错误，预约的时间不会归还
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
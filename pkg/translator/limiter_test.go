@@ -0,0 +1,124 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetGlobalConcurrency(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	const limit = 2
+	SetGlobalConcurrency(limit)
+	t.Cleanup(func() { SetGlobalConcurrency(0) })
+
+	var inFlight, peak atomic.Int32
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return "译文", nil
+	}}
+
+	// 多个批量调用同时进行，各自的并发上限之和超过全局上限
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for b := 0; b < 4; b++ {
+		texts := make([]string, 4)
+		for i := range texts {
+			texts[i] = fmt.Sprintf("batch %d text %d", b, i)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := TranslateBatch(context.Background(), llm, texts, "English", "Chinese"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak in-flight LLM calls = %d, want at most %d", got, limit)
+	}
+	if n := llm.Calls(); n != 16 {
+		t.Errorf("LLM called %d times, want 16", n)
+	}
+}
+
+func TestAcquireGlobal_ContextCancelled(t *testing.T) {
+	SetGlobalConcurrency(1)
+	t.Cleanup(func() { SetGlobalConcurrency(0) })
+
+	release, err := acquireGlobal(context.Background())
+	if err != nil {
+		t.Fatalf("acquireGlobal() error = %v", err)
+	}
+	defer release()
+
+	// 额度被占满时，等待随 ctx 结束而放弃
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := acquireGlobal(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquireGlobal() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSetRateLimiter_PerLanguagePair(t *testing.T) {
+	SetRateLimiter("English", "Chinese", NewIntervalLimiter(100*time.Millisecond))
+	SetDefaultRateLimiter(NewIntervalLimiter(time.Millisecond))
+	t.Cleanup(func() {
+		SetRateLimiter("English", "Chinese", nil)
+		SetDefaultRateLimiter(nil)
+	})
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"One": "一", "Two": "二", "Three": "三"})
+	ctx := context.Background()
+
+	translateAll := func(out string) time.Duration {
+		start := time.Now()
+		for _, text := range []string{"One", "Two", "Three"} {
+			if _, err := Translate(ctx, llm, text, "English", out, WithNoCache()); err != nil {
+				t.Fatalf("Translate() to %s error = %v", out, err)
+			}
+		}
+		return time.Since(start)
+	}
+
+	// 严格的语言对：三次调用之间至少间隔两次 100ms
+	if elapsed := translateAll("chinese"); elapsed < 200*time.Millisecond {
+		t.Errorf("strict pair took %v, want at least 200ms", elapsed)
+	}
+	// 其他语言对使用宽松的默认限流器
+	if elapsed := translateAll("French"); elapsed >= 100*time.Millisecond {
+		t.Errorf("lax pair took %v, want well under 100ms", elapsed)
+	}
+}
+
+func TestIntervalLimiter_ContextCancelled(t *testing.T) {
+	l := NewIntervalLimiter(time.Hour)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,72 @@
+package translator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateLines 读取 r 中以换行分隔的文本，去重后批量翻译，再按原顺序逐行写入 w。
+// 输出与输入行行对应：空行原样保留，每行的换行符（\n 或 \r\n）与输入一致，
+// 输入末尾没有换行符时输出末尾同样没有。与 TranslateStreamReader 不同，它会先读入全部内容
+func TranslateLines(ctx context.Context, llm llms.Model, r io.Reader, w io.Writer, inputLanguage string, outputLanguage string, opts ...Option) error {
+	// 拆分每行的内容和行尾
+	var contents, endings []string
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read line %d: %w", len(contents)+1, err)
+		}
+		if line != "" {
+			content := strings.TrimRight(line, "\r\n")
+			contents = append(contents, content)
+			endings = append(endings, line[len(content):])
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	// 去重后批量翻译
+	var texts []string
+	seen := make(map[string]bool)
+	for _, content := range contents {
+		if strings.TrimSpace(content) == "" || seen[content] {
+			continue
+		}
+		seen[content] = true
+		texts = append(texts, content)
+	}
+
+	translations := make(map[string]string, len(texts))
+	if len(texts) > 0 {
+		results, err := TranslateBatch(ctx, llm, texts, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return err
+		}
+		for i, text := range texts {
+			translations[text] = results[i]
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, content := range contents {
+		translated, ok := translations[content]
+		if !ok {
+			translated = content
+		}
+		if _, err := bw.WriteString(translated + endings[i]); err != nil {
+			return fmt.Errorf("failed to write line %d: %w", i+1, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateLines(t *testing.T) {
+	withoutBatchDelay(t)
+	llm := newDictLLM(map[string]string{
+		"Hello":     "你好",
+		"Thank you": "谢谢",
+		"Goodbye":   "再见",
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Blank Lines Preserved",
+			input: "Hello\n\nThank you\n   \nGoodbye\n",
+			want:  "你好\n\n谢谢\n   \n再见\n",
+		},
+		{
+			name:  "No Trailing Newline",
+			input: "Hello\nGoodbye",
+			want:  "你好\n再见",
+		},
+		{
+			name:  "CRLF And Duplicates",
+			input: "Hello\r\nHello\r\n\r\nThank you",
+			want:  "你好\r\n你好\r\n\r\n谢谢",
+		},
+		{
+			name:  "Only Blank Lines",
+			input: "\n\n",
+			want:  "\n\n",
+		},
+		{
+			name:  "Empty Input",
+			input: "",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			var out strings.Builder
+			if err := TranslateLines(context.Background(), llm, strings.NewReader(tt.input), &out, "English", "Chinese"); err != nil {
+				t.Fatalf("TranslateLines() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("TranslateLines() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateLines_Dedup(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	var out strings.Builder
+	if err := TranslateLines(context.Background(), llm, strings.NewReader("Hello\nHello\nHello\n"), &out, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateLines() error = %v", err)
+	}
+	if out.String() != "你好\n你好\n你好\n" {
+		t.Errorf("TranslateLines() = %q", out.String())
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("LLM called %d times, want 1 for duplicate lines", llm.Calls())
+	}
+}
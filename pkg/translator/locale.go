@@ -0,0 +1,115 @@
+package translator
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+)
+
+// localeTagPattern 匹配 BCP-47 形式的语言标签，如 "zh-TW"、"en_GB"、"zh-Hant-HK"
+var localeTagPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(?:[-_][A-Za-z0-9]{2,8})*$`)
+
+// localeNames 把常见的带地区或文字的标签（小写）映射为提示词中使用的名称，
+// 用来区分简体和繁体中文、英式和美式拼写等差异
+var localeNames = map[string]string{
+	"zh-cn":   "Simplified Chinese",
+	"zh-sg":   "Simplified Chinese",
+	"zh-hans": "Simplified Chinese",
+	"zh-tw":   "Traditional Chinese",
+	"zh-hk":   "Traditional Chinese",
+	"zh-mo":   "Traditional Chinese",
+	"zh-hant": "Traditional Chinese",
+	"en-us":   "American English",
+	"en-gb":   "British English",
+	"en-au":   "Australian English",
+	"en-ca":   "Canadian English",
+	"pt-br":   "Brazilian Portuguese",
+	"pt-pt":   "European Portuguese",
+	"es-es":   "European Spanish",
+	"es-mx":   "Mexican Spanish",
+	"fr-ca":   "Canadian French",
+	"fr-fr":   "French",
+}
+
+// isLocaleTag 判断语言参数是否为 BCP-47 标签而不是语言名称
+func isLocaleTag(lang string) bool {
+	return localeTagPattern.MatchString(strings.TrimSpace(lang))
+}
+
+// canonicalLocale 把 BCP-47 标签规范为标准大小写：语言小写、文字首字母大写、地区大写，
+// 如 "zh_tw" -> "zh-TW"、"ZH-hant-hk" -> "zh-Hant-HK"。语言名称原样返回
+func canonicalLocale(lang string) string {
+	if !isLocaleTag(lang) {
+		return lang
+	}
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"), "-")
+	for i, subtag := range subtags {
+		switch {
+		case i == 0:
+			subtags[i] = strings.ToLower(subtag)
+		case len(subtag) == 4:
+			subtags[i] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2:
+			subtags[i] = strings.ToUpper(subtag)
+		default:
+			subtags[i] = strings.ToLower(subtag)
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
+// describeLanguage 把语言参数转换为提示词中的描述。BCP-47 标签展开为带标签的名称，
+// 如 "zh-TW" -> "Traditional Chinese (zh-TW)"、"en-GB" -> "British English (en-GB)"，
+// 让模型使用对应地区的文字和拼写；语言名称原样返回
+func describeLanguage(lang string) string {
+	if !isLocaleTag(lang) {
+		return lang
+	}
+	tag := canonicalLocale(lang)
+	subtags := strings.Split(strings.ToLower(tag), "-")
+
+	// 依次尝试 语言-文字-地区 中由长到短的前缀，以及 语言-地区
+	name := ""
+	for n := len(subtags); n >= 2 && name == ""; n-- {
+		name = localeNames[strings.Join(subtags[:n], "-")]
+	}
+	if name == "" && len(subtags) > 2 {
+		name = localeNames[subtags[0]+"-"+subtags[len(subtags)-1]]
+	}
+	if name == "" {
+		name = normalizeLanguage(tag)
+		if name == tag {
+			return tag
+		}
+	}
+	return name + " (" + tag + ")"
+}
+
+// sameBaseLanguage 判断两个语言参数是否为同一种语言，忽略地区和文字的差异。
+// 语言代码按 normalizeLanguage 转为名称后比较，如 "Chinese" 与 "zh-CN"、"en_GB" 与 "English" 视为相同
+func sameBaseLanguage(a, b string) bool {
+	return strings.EqualFold(normalizeLanguage(a), normalizeLanguage(b))
+}
+
+// localeValueNames 是提示词变量中表示语言的变量名
+var localeValueNames = []string{"inputLanguage", "outputLanguage", "language"}
+
+// describeLanguageValues 返回把语言变量替换为 describeLanguage 描述后的变量；
+// 没有 BCP-47 标签时直接返回 values，否则返回副本，不修改调用方的 map
+func describeLanguageValues(values map[string]any) map[string]any {
+	var described map[string]any
+	for _, name := range localeValueNames {
+		lang, ok := values[name].(string)
+		if !ok || !isLocaleTag(lang) {
+			continue
+		}
+		if described == nil {
+			described = maps.Clone(values)
+		}
+		described[name] = describeLanguage(lang)
+	}
+	if described == nil {
+		return values
+	}
+	return described
+}
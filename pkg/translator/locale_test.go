@@ -0,0 +1,75 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDescribeLanguage(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: "Chinese", want: "Chinese"},
+		{lang: "zh-CN", want: "Simplified Chinese (zh-CN)"},
+		{lang: "zh_tw", want: "Traditional Chinese (zh-TW)"},
+		{lang: "zh-Hant-HK", want: "Traditional Chinese (zh-Hant-HK)"},
+		{lang: "en-GB", want: "British English (en-GB)"},
+		{lang: "de-AT", want: "German (de-AT)"},
+		{lang: "ja", want: "Japanese (ja)"},
+		{lang: "xx-YY", want: "xx-YY"},
+	}
+
+	for _, tt := range tests {
+		if got := describeLanguage(tt.lang); got != tt.want {
+			t.Errorf("describeLanguage(%q) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate_LocaleTags(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Traditional Chinese (zh-TW)") {
+			return "軟體", nil
+		}
+		if strings.Contains(prompt, "Simplified Chinese (zh-CN)") {
+			return "软件", nil
+		}
+		return "", nil
+	}}
+
+	cn, err := Translate(ctx, llm, "software", "en-US", "zh-CN")
+	if err != nil {
+		t.Fatalf("Translate(zh-CN) error = %v", err)
+	}
+	tw, err := Translate(ctx, llm, "software", "en-US", "zh-TW")
+	if err != nil {
+		t.Fatalf("Translate(zh-TW) error = %v", err)
+	}
+	if cn != "软件" || tw != "軟體" {
+		t.Errorf("Translate() = %q, %q, want 软件, 軟體", cn, tw)
+	}
+
+	// 两个地区的提示词不同
+	prompts := llm.prompts
+	if len(prompts) != 2 || prompts[0] == prompts[1] {
+		t.Fatalf("prompts = %q, want two distinct prompts", prompts)
+	}
+	if !strings.Contains(prompts[0], "American English (en-US)") {
+		t.Errorf("prompt does not describe the source locale: %s", prompts[0])
+	}
+
+	// 缓存条目按完整的地区标签区分，标签的写法不影响命中
+	if cached, _ := defaultCache.Get("software", "en-US", "zh-CN"); cached != "软件" {
+		t.Errorf("zh-CN cache = %q, want 软件", cached)
+	}
+	if cached, _ := defaultCache.Get("software", "en_us", "zh_tw"); cached != "軟體" {
+		t.Errorf("zh-TW cache = %q, want 軟體", cached)
+	}
+	if _, ok := defaultCache.Get("software", "English", "Chinese"); ok {
+		t.Error("locale entries should not match the plain language names")
+	}
+}
@@ -0,0 +1,133 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+var (
+	// mdFencePattern 匹配围栏代码块的起止行
+	mdFencePattern = regexp.MustCompile("^\\s*(`{3,}|~{3,})")
+	// mdPrefixPattern 匹配行首的块级标记：标题、引用、列表
+	mdPrefixPattern = regexp.MustCompile(`^(?:\s*(?:#{1,6}\s+|>\s?|[-*+]\s+|\d+[.)]\s+))*\s*`)
+	// mdInlinePattern 匹配需要特殊处理的行内元素：行内代码、链接/图片、自动链接、裸 URL
+	mdInlinePattern = regexp.MustCompile("`[^`]*`|!?\\[([^\\]]*)\\]\\(([^)]*)\\)|<https?://[^>]+>|https?://\\S+")
+)
+
+// TranslateMarkdown 翻译 Markdown 文档中的正文，保留格式结构。
+// 围栏代码块和行内代码不翻译；标题、引用和列表标记原样保留；
+// 链接文字会被翻译，但链接地址保持不变。opts 原样传给每一段正文的 Translate 调用。
+func TranslateMarkdown(ctx context.Context, llm llms.Model, markdown string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
+	if strings.TrimSpace(markdown) == "" {
+		return "", ErrEmptyText
+	}
+
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		content := strings.TrimSuffix(line, "\r")
+		ending := line[len(content):]
+
+		// 围栏代码块内部原样保留，直到遇到同类型的结束标记
+		if m := mdFencePattern.FindStringSubmatch(content); m != nil {
+			if fence == "" {
+				fence = m[1][:1]
+			} else if m[1][:1] == fence {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		translated, err := translateMarkdownLine(ctx, llm, content, inputLanguage, outputLanguage, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to translate line %d: %w", i+1, err)
+		}
+		lines[i] = translated + ending
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// translateMarkdownLine 翻译单行内容，保留行首的块级标记
+func translateMarkdownLine(ctx context.Context, llm llms.Model, line string, inputLanguage string, outputLanguage string, opts []Option) (string, error) {
+	prefix := mdPrefixPattern.FindString(line)
+	rest := line[len(prefix):]
+
+	// 表格行按单元格分别翻译
+	if strings.HasPrefix(rest, "|") {
+		cells := strings.Split(rest, "|")
+		for i, cell := range cells {
+			translated, err := translateMarkdownInline(ctx, llm, cell, inputLanguage, outputLanguage, opts)
+			if err != nil {
+				return "", err
+			}
+			cells[i] = translated
+		}
+		return prefix + strings.Join(cells, "|"), nil
+	}
+
+	translated, err := translateMarkdownInline(ctx, llm, rest, inputLanguage, outputLanguage, opts)
+	if err != nil {
+		return "", err
+	}
+	return prefix + translated, nil
+}
+
+// translateMarkdownInline 翻译行内的正文片段，跳过代码和 URL，只翻译链接文字
+func translateMarkdownInline(ctx context.Context, llm llms.Model, s string, inputLanguage string, outputLanguage string, opts []Option) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, m := range mdInlinePattern.FindAllStringSubmatchIndex(s, -1) {
+		run, err := translateMarkdownRun(ctx, llm, s[last:m[0]], inputLanguage, outputLanguage, opts)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(run)
+
+		token := s[m[0]:m[1]]
+		if m[2] >= 0 {
+			// 链接或图片：翻译文字部分，保留地址
+			text, err := translateMarkdownRun(ctx, llm, s[m[2]:m[3]], inputLanguage, outputLanguage, opts)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(token, "!") {
+				b.WriteString("!")
+			}
+			b.WriteString("[" + text + "](" + s[m[4]:m[5]] + ")")
+		} else {
+			b.WriteString(token)
+		}
+		last = m[1]
+	}
+
+	run, err := translateMarkdownRun(ctx, llm, s[last:], inputLanguage, outputLanguage, opts)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(run)
+	return b.String(), nil
+}
+
+// translateMarkdownRun 翻译一段正文，保留首尾空白；不含字母的片段（标点、分隔线等）原样返回
+func translateMarkdownRun(ctx context.Context, llm llms.Model, run string, inputLanguage string, outputLanguage string, opts []Option) (string, error) {
+	trimmed := strings.TrimSpace(run)
+	if strings.IndexFunc(trimmed, unicode.IsLetter) < 0 {
+		return run, nil
+	}
+
+	translated, err := Translate(ctx, llm, trimmed, inputLanguage, outputLanguage, opts...)
+	if err != nil {
+		return "", err
+	}
+	start := strings.Index(run, trimmed)
+	return run[:start] + translated + run[start+len(trimmed):], nil
+}
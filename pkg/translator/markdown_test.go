@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTranslateMarkdown(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Getting started":   "入门",
+		"Install the tool:": "安装工具：",
+		"Read the":          "阅读",
+		"documentation":     "文档",
+		"for details.":      "了解详情。",
+		"Run":               "运行",
+		"to build.":         "来构建。",
+		"First step":        "第一步",
+		"A quoted note":     "一条引用说明",
+		"Name":              "名称",
+		"Value":             "值",
+		"Logo":              "标志",
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Heading",
+			input: "## Getting started",
+			want:  "## 入门",
+		},
+		{
+			name:  "Fenced Code Block Untouched",
+			input: "Install the tool:\n\n```bash\n# Getting started\ngo install ./...\n```\n",
+			want:  "安装工具：\n\n```bash\n# Getting started\ngo install ./...\n```\n",
+		},
+		{
+			name:  "Link Text Translated URL Preserved",
+			input: "Read the [documentation](https://example.com/docs) for details.",
+			want:  "阅读 [文档](https://example.com/docs) 了解详情。",
+		},
+		{
+			name:  "Inline Code Untouched",
+			input: "Run `go build` to build.",
+			want:  "运行 `go build` 来构建。",
+		},
+		{
+			name:  "List And Quote Markers",
+			input: "- First step\n> A quoted note",
+			want:  "- 第一步\n> 一条引用说明",
+		},
+		{
+			name:  "Table",
+			input: "| Name | Value |\n|------|-------|",
+			want:  "| 名称 | 值 |\n|------|-------|",
+		},
+		{
+			name:  "Image Alt And Bare URL",
+			input: "![Logo](logo.png) https://example.com",
+			want:  "![标志](logo.png) https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TranslateMarkdown(context.Background(), llm, tt.input, "English", "Chinese")
+			if err != nil {
+				t.Fatalf("TranslateMarkdown() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslateMarkdown() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package translator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateMessages 翻译一组消息（如整段对话）中的文本部分，返回新的消息切片，msgs 本身不会被修改。
+// 每条消息的角色、部分的顺序以及图片、工具调用等非文本部分原样保留；
+// 所有文本去重后通过 TranslateBatch 一次性翻译，空白文本保持不变
+func TranslateMessages(ctx context.Context, llm llms.Model, msgs []llms.MessageContent, inputLanguage string, outputLanguage string, opts ...Option) ([]llms.MessageContent, error) {
+	var texts []string
+	seen := make(map[string]bool)
+	for _, msg := range msgs {
+		for _, part := range msg.Parts {
+			text, ok := part.(llms.TextContent)
+			if !ok || strings.TrimSpace(text.Text) == "" || seen[text.Text] {
+				continue
+			}
+			seen[text.Text] = true
+			texts = append(texts, text.Text)
+		}
+	}
+
+	translations := make(map[string]string, len(texts))
+	if len(texts) > 0 {
+		results, err := TranslateBatch(ctx, llm, texts, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for i, text := range texts {
+			translations[text] = results[i]
+		}
+	}
+
+	translated := make([]llms.MessageContent, len(msgs))
+	for i, msg := range msgs {
+		parts := make([]llms.ContentPart, len(msg.Parts))
+		for j, part := range msg.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				if result, ok := translations[text.Text]; ok {
+					part = llms.TextContent{Text: result}
+				}
+			}
+			parts[j] = part
+		}
+		translated[i] = llms.MessageContent{Role: msg.Role, Parts: parts}
+	}
+	return translated, nil
+}
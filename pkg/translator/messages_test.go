@@ -0,0 +1,66 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestTranslateMessages(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"You are a helpful assistant": "你是一个乐于助人的助手",
+		"What is in this picture?":    "这张图片里有什么？",
+		"A cat":                       "一只猫",
+	})
+
+	image := llms.ImageURLContent{URL: "https://example.com/cat.png"}
+	toolCall := llms.ToolCall{ID: "call-1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"q":"cat"}`}}
+	msgs := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a helpful assistant"),
+		{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{llms.TextContent{Text: "What is in this picture?"}, image}},
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{toolCall, llms.TextContent{Text: "A cat"}, llms.TextContent{Text: " "}}},
+	}
+	original := make([]llms.MessageContent, len(msgs))
+	for i, msg := range msgs {
+		original[i] = llms.MessageContent{Role: msg.Role, Parts: append([]llms.ContentPart(nil), msg.Parts...)}
+	}
+
+	got, err := TranslateMessages(context.Background(), llm, msgs, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateMessages() error = %v", err)
+	}
+
+	want := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "你是一个乐于助人的助手"),
+		{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{llms.TextContent{Text: "这张图片里有什么？"}, image}},
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{toolCall, llms.TextContent{Text: "一只猫"}, llms.TextContent{Text: " "}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateMessages() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// 输入的消息不被修改
+	if !reflect.DeepEqual(msgs, original) {
+		t.Errorf("input messages were modified: %+v", msgs)
+	}
+}
+
+func TestTranslateMessages_NoText(t *testing.T) {
+	llm := newDictLLM(nil)
+	msgs := []llms.MessageContent{{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{llms.ImageURLContent{URL: "https://example.com/a.png"}}}}
+
+	got, err := TranslateMessages(context.Background(), llm, msgs, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateMessages() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, msgs) {
+		t.Errorf("TranslateMessages() = %+v, want unchanged", got)
+	}
+	if llm.Calls() != 0 {
+		t.Errorf("LLM called %d times, want 0", llm.Calls())
+	}
+}
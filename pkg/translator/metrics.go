@@ -0,0 +1,71 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ItemMetric 是批量翻译中单条文本的耗时和缓存命中情况
+type ItemMetric struct {
+	Index    int           // 在输入 texts 中的下标
+	Duration time.Duration // 从开始处理到得到结果的耗时，不含等待并发额度的时间；命中缓存时为批量查询缓存的耗时
+	CacheHit bool          // 是否直接命中缓存，没有调用模型
+	Err      error         // 该条的翻译错误；FallbackKeepOriginal 回退时为回退原因
+}
+
+// TranslateBatchWithMetrics 与 TranslateBatch 使用相同的缓存预查、分批延迟、并发限制和失败回退，
+// 但同时返回每条文本的耗时和缓存命中情况，用于分析慢条目和缓存效果。
+// 与 TranslateBatch 不同，单条失败不会中断后续批次：即使返回错误，译文和指标也都是完整的，
+// 失败条目的译文为空（回退时为原文）。默认（FallbackStrict）返回下标最小的失败条目的错误
+func TranslateBatchWithMetrics(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]string, []ItemMetric, error) {
+	if len(texts) == 0 {
+		return nil, nil, fmt.Errorf("empty texts input")
+	}
+
+	o := newOptions(opts)
+	translations := make([]string, len(texts))
+	metrics := make([]ItemMetric, len(texts))
+
+	// 一次性查缓存，命中的条目耗时记为查询缓存的时间，只把未命中的文本交给工作循环
+	start := time.Now()
+	hits := o.cacheGetMany(ctx, llm, texts, inputLanguage, outputLanguage)
+	lookup := time.Since(start)
+	var pending []int
+	for i := range texts {
+		if result, ok := hits[i]; ok {
+			translations[i] = result
+			metrics[i] = ItemMetric{Index: i, Duration: lookup, CacheHit: true}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	// 与 TranslateBatch 共用工作循环；错误记录在指标中，不中断后续批次
+	semaphore := make(chan struct{}, maxConcurrency)
+	var workers sync.WaitGroup
+	_ = runBatches(pending, semaphore, &workers, func(index int) batchItem {
+		start := time.Now()
+		result := translateStreamItem(ctx, llm, texts[index], inputLanguage, outputLanguage, o, opts)
+		return batchItem{index: index, result: result, duration: time.Since(start)}
+	}, func(item batchItem) error {
+		translations[item.index] = item.result.Text
+		metrics[item.index] = ItemMetric{Index: item.index, Duration: item.duration, Err: item.result.Err}
+		return nil
+	})
+	if err := o.checkConcurrency(semaphore, &workers); err != nil {
+		return translations, metrics, err
+	}
+
+	if o.failureFallback != FallbackKeepOriginal {
+		for _, m := range metrics {
+			if m.Err != nil {
+				return translations, metrics, fmt.Errorf("batch translation error: failed to translate text at index %d: %w", m.Index, m.Err)
+			}
+		}
+	}
+	return translations, metrics, nil
+}
@@ -0,0 +1,126 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTranslateBatchWithMetrics(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	defaultCache.Set("Hello", "English", "Chinese", "你好")
+
+	llm := newDictLLM(map[string]string{"Thank you": "谢谢", "Goodbye": "再见"})
+	respond := llm.respond
+	llm.respond = func(prompt string) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return respond(prompt)
+	}
+
+	texts := []string{"Hello", "Thank you", "Goodbye"}
+	got, metrics, err := TranslateBatchWithMetrics(context.Background(), llm, texts, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchWithMetrics() error = %v", err)
+	}
+	if want := []string{"你好", "谢谢", "再见"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("translations = %q, want %q", got, want)
+	}
+
+	wantHits := []bool{true, false, false}
+	for i, m := range metrics {
+		if m.Index != i {
+			t.Errorf("metrics[%d].Index = %d", i, m.Index)
+		}
+		if m.CacheHit != wantHits[i] {
+			t.Errorf("metrics[%d].CacheHit = %v, want %v", i, m.CacheHit, wantHits[i])
+		}
+		if m.Err != nil {
+			t.Errorf("metrics[%d].Err = %v", i, m.Err)
+		}
+		if !m.CacheHit && m.Duration < 5*time.Millisecond {
+			t.Errorf("metrics[%d].Duration = %v, want at least the model latency", i, m.Duration)
+		}
+	}
+
+	// 再次翻译时全部命中缓存
+	_, metrics, err = TranslateBatchWithMetrics(context.Background(), llm, texts, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchWithMetrics() error = %v", err)
+	}
+	for i, m := range metrics {
+		if !m.CacheHit {
+			t.Errorf("second run metrics[%d].CacheHit = false, want true", i)
+		}
+	}
+}
+
+func TestTranslateBatchWithMetrics_ItemError(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	got, metrics, err := TranslateBatchWithMetrics(context.Background(), llm, []string{"Hello", ""}, "English", "Chinese")
+	if !errors.Is(err, ErrEmptyText) {
+		t.Fatalf("TranslateBatchWithMetrics() error = %v, want ErrEmptyText", err)
+	}
+	if got[0] != "你好" || metrics[0].Err != nil {
+		t.Errorf("item 0 = %q, %v, want successful translation", got[0], metrics[0].Err)
+	}
+	if !errors.Is(metrics[1].Err, ErrEmptyText) {
+		t.Errorf("metrics[1].Err = %v, want ErrEmptyText", metrics[1].Err)
+	}
+}
+
+func TestTranslateBatchWithMetrics_Batches(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+
+	// 记录同时进行的调用数，以及每条文本开始时已完成的文本数
+	texts := make([]string, batchSize+2)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Sentence %d", i)
+	}
+	var mu sync.Mutex
+	inFlight, maxInFlight, done := 0, 0, 0
+	doneAtStart := make(map[string]int)
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		text := ""
+		for _, candidate := range texts {
+			if strings.Contains(prompt, `"`+candidate+`"`) {
+				text = candidate
+			}
+		}
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		doneAtStart[text] = done
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		done++
+		mu.Unlock()
+		return "句子", nil
+	}}
+
+	if _, _, err := TranslateBatchWithMetrics(context.Background(), llm, texts, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateBatchWithMetrics() error = %v", err)
+	}
+	if maxInFlight > maxConcurrency {
+		t.Errorf("%d concurrent LLM calls, want at most %d", maxInFlight, maxConcurrency)
+	}
+	// 第二批的文本在第一批全部完成后才开始
+	for _, text := range texts[batchSize:] {
+		if doneAtStart[text] < batchSize {
+			t.Errorf("%q started after %d completed translations, want at least %d (next batch)", text, doneAtStart[text], batchSize)
+		}
+	}
+}
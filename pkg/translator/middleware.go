@@ -0,0 +1,77 @@
+package translator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateFunc 是翻译管道中的一环：把 text 从 inputLanguage 翻译为 outputLanguage
+type TranslateFunc func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error)
+
+// Middleware 包装一个 TranslateFunc，在其前后加入日志、指标、限流、缓存等横切逻辑
+type Middleware func(next TranslateFunc) TranslateFunc
+
+// Chain 把多个中间件组合为一个，第一个中间件在最外层，最先被调用。
+// 例如 Chain(LoggingMiddleware(nil), CacheMiddleware(cache))(NewTranslateFunc(llm))
+// 先记录日志，再查缓存，未命中时才调用模型
+func Chain(mw ...Middleware) Middleware {
+	return func(next TranslateFunc) TranslateFunc {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
+// NewTranslateFunc 返回直接调用模型翻译的 TranslateFunc，作为中间件管道的最内层。
+// 它不读写共享缓存（相当于附加了 WithNoCache），缓存交由 CacheMiddleware 负责，便于调整顺序或替换
+func NewTranslateFunc(llm llms.Model, opts ...Option) TranslateFunc {
+	opts = append(append([]Option(nil), opts...), WithNoCache())
+	return func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+		return Translate(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+	}
+}
+
+// CacheMiddleware 用 c 缓存翻译结果：命中时直接返回，未命中时调用下一层并缓存成功的结果。
+// c 为 nil 时使用共享的默认缓存
+func CacheMiddleware(c *TranslationCache) Middleware {
+	if c == nil {
+		c = defaultCache
+	}
+	return func(next TranslateFunc) TranslateFunc {
+		return func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+			if result, ok := c.Get(text, inputLanguage, outputLanguage); ok {
+				return result, nil
+			}
+			result, err := next(ctx, text, inputLanguage, outputLanguage)
+			if err != nil {
+				return result, err
+			}
+			c.Set(text, inputLanguage, outputLanguage, result)
+			return result, nil
+		}
+	}
+}
+
+// LoggingMiddleware 记录每次翻译的开始、耗时和结果。logger 为 nil 时使用 log 包的默认 Logger
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next TranslateFunc) TranslateFunc {
+		return func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+			logger.Printf("Translating '%s' from %s to %s", text, inputLanguage, outputLanguage)
+			start := time.Now()
+			result, err := next(ctx, text, inputLanguage, outputLanguage)
+			if err != nil {
+				logger.Printf("Translation of '%s' failed after %s: %v", text, time.Since(start), err)
+				return result, err
+			}
+			logger.Printf("Translated '%s' in %s: %s", text, time.Since(start), result)
+			return result, nil
+		}
+	}
+}
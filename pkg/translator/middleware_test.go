@@ -0,0 +1,99 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// spyMiddleware 在调用下一层前后记录自己的名字
+func spyMiddleware(name string, calls *[]string) Middleware {
+	return func(next TranslateFunc) TranslateFunc {
+		return func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+			*calls = append(*calls, name+" before")
+			result, err := next(ctx, text, inputLanguage, outputLanguage)
+			*calls = append(*calls, name+" after")
+			return result, err
+		}
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	base := func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+		calls = append(calls, "translate")
+		return "你好", nil
+	}
+
+	translate := Chain(spyMiddleware("outer", &calls), spyMiddleware("inner", &calls))(base)
+	result, err := translate(context.Background(), "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("translate() = %q, want %q", result, "你好")
+	}
+
+	want := []string{"outer before", "inner before", "translate", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("invocation order = %q, want %q", calls, want)
+	}
+}
+
+func TestChain_CacheAndLogging(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	cache := NewTranslationCache()
+	var logs bytes.Buffer
+	var calls []string
+
+	// 缓存在 spy 之外：命中时 spy 和模型都不会被调用
+	translate := Chain(
+		LoggingMiddleware(log.New(&logs, "", 0)),
+		CacheMiddleware(cache),
+		spyMiddleware("spy", &calls),
+	)(NewTranslateFunc(llm))
+
+	for i := 0; i < 2; i++ {
+		result, err := translate(ctx, "Hello", "English", "Chinese")
+		if err != nil {
+			t.Fatalf("translate() error = %v", err)
+		}
+		if result != "你好" {
+			t.Errorf("translate() = %q, want %q", result, "你好")
+		}
+	}
+
+	if llm.Calls() != 1 || len(calls) != 2 {
+		t.Errorf("LLM calls = %d, spy calls = %q, want one uncached call", llm.Calls(), calls)
+	}
+	if _, ok := cache.Get("Hello", "English", "Chinese"); !ok {
+		t.Error("result not stored in the middleware cache")
+	}
+	// 管道最内层不写共享缓存
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("NewTranslateFunc should not write the shared cache")
+	}
+	if got := strings.Count(logs.String(), "Translated 'Hello'"); got != 2 {
+		t.Errorf("logged %d translations, want 2:\n%s", got, logs.String())
+	}
+}
+
+func TestCacheMiddleware_ErrorNotCached(t *testing.T) {
+	cache := NewTranslationCache()
+	failing := func(ctx context.Context, text string, inputLanguage string, outputLanguage string) (string, error) {
+		return "", errors.New("boom")
+	}
+
+	if _, err := CacheMiddleware(cache)(failing)(context.Background(), "Hello", "English", "Chinese"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := cache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("failed translation should not be cached")
+	}
+}
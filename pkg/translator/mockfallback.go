@@ -0,0 +1,40 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	"github.com/tmc/langchaingo/llms"
+
+	"github.com/costa92/langchaingo-demo/pkg/mock"
+)
+
+// WithMockFallback 在没有配置模型（llm 为 nil）或提供方拒绝认证（ErrUnauthorized，通常是缺少或填错 API key）时，
+// 改用 mock.MockTranslator 返回模拟译文而不是错误，方便本地开发。
+// used 不为 nil 时，任一条目使用了模拟译文都会把它置为 true，不会重置为 false，
+// 批量翻译等并发路径可以安全地共用同一个 used；模拟译文不会写入缓存
+func WithMockFallback(used *atomic.Bool) Option {
+	return func(o *options) {
+		o.mockFallback = true
+		o.mockFallbackUsed = used
+	}
+}
+
+// shouldMock 判断本次翻译是否应改用模拟译文
+func (o *options) shouldMock(llm llms.Model, err error) bool {
+	if !o.mockFallback {
+		return false
+	}
+	return llm == nil || errors.Is(err, ErrUnauthorized)
+}
+
+// translateMock 用模拟翻译器翻译文本，并记录使用了回退
+func (o *options) translateMock(ctx context.Context, text string) (string, error) {
+	log.Printf("LLM is not configured, using mock translation for: %s", text)
+	if o.mockFallbackUsed != nil {
+		o.mockFallbackUsed.Store(true)
+	}
+	return mock.NewMockTranslator().Call(ctx, text)
+}
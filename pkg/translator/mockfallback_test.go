@@ -0,0 +1,73 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTranslate_MockFallbackNilLLM(t *testing.T) {
+	defaultCache.Clear()
+	var used atomic.Bool
+	got, err := Translate(context.Background(), nil, "Hello world", "English", "Chinese", WithMockFallback(&used))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "你好，世界" {
+		t.Errorf("Translate() = %q, want mock result %q", got, "你好，世界")
+	}
+	if !used.Load() {
+		t.Error("expected fallback flag to be set")
+	}
+	if cacheLen(defaultCache) != 0 {
+		t.Error("mock result should not be cached")
+	}
+}
+
+func TestTranslate_MockFallbackUnauthorized(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := &fakeLLM{respond: func(string) (string, error) {
+		return "", fmt.Errorf("invalid api key: %w", ErrUnauthorized)
+	}}
+
+	var used atomic.Bool
+	got, err := Translate(ctx, llm, "Thank you", "English", "Chinese", WithMockFallback(&used))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "谢谢" || !used.Load() {
+		t.Errorf("Translate() = %q, used = %v, want mock result %q", got, used.Load(), "谢谢")
+	}
+
+	// 正常的模型调用不使用回退，未开启时照常返回错误
+	var notUsed atomic.Bool
+	ok := newDictLLM(map[string]string{"Thank you": "多谢"})
+	if got, err := Translate(ctx, ok, "Thank you", "English", "Chinese", WithMockFallback(&notUsed)); err != nil || got != "多谢" || notUsed.Load() {
+		t.Errorf("Translate() = %q, %v, used = %v, want model result without fallback", got, err, notUsed.Load())
+	}
+	defaultCache.Clear()
+	if _, err := Translate(ctx, llm, "Thank you", "English", "Chinese"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Translate() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestTranslateBatch_MockFallbackConcurrent(t *testing.T) {
+	defaultCache.Clear()
+	texts := []string{"Hello world", "Thank you", "Good morning", "Goodbye"}
+
+	// 每个条目在各自的 goroutine 中回退，共用同一个标记；用 -race 运行时不应报告数据竞争
+	var used atomic.Bool
+	got, err := TranslateBatch(context.Background(), nil, texts, "English", "Chinese", WithMockFallback(&used))
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if len(got) != len(texts) {
+		t.Fatalf("TranslateBatch() returned %d results, want %d", len(got), len(texts))
+	}
+	if !used.Load() {
+		t.Error("expected fallback flag to be set")
+	}
+}
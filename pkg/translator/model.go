@@ -0,0 +1,35 @@
+package translator
+
+import "context"
+
+// modelOverrideKey 是 context 中保存模型覆盖值的键
+type modelOverrideKey struct{}
+
+// WithModelOverride 返回携带模型名称的 context，使用它发起的翻译调用都会改用该模型，
+// 便于中间件按租户选择模型而不必在每个函数间传递。model 为空时不覆盖
+func WithModelOverride(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, modelOverrideKey{}, model)
+}
+
+// modelOverride 返回 context 中设置的模型名称
+func modelOverride(ctx context.Context) (string, bool) {
+	model, ok := ctx.Value(modelOverrideKey{}).(string)
+	return model, ok && model != ""
+}
+
+// WithModel 让本次翻译改用指定的模型，例如为代码或诗歌选用更强的模型，优先于 WithModelOverride。
+// 模型名称通过调用选项（llms.WithModel）传给客户端，提供方不支持按调用选择模型时不起作用。
+// 模型名称（包括 context 中的覆盖值）计入缓存键，不同模型的译文分开缓存
+func WithModel(name string) Option {
+	return func(o *options) {
+		o.model = name
+	}
+}
+
+// modelName 返回本次调用使用的模型：WithModel 优先，其次是 context 中的覆盖值
+func (o *options) modelName(ctx context.Context) (string, bool) {
+	if o.model != "" {
+		return o.model, true
+	}
+	return modelOverride(ctx)
+}
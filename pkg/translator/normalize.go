@@ -0,0 +1,82 @@
+package translator
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// quotePairs 是模型常用来包裹整段译文的引号对，直引号和弯引号一视同仁
+var quotePairs = map[rune]rune{
+	'"':  '"',
+	'\'': '\'',
+	'“':  '”',
+	'‘':  '’',
+	'「':  '」',
+	'『':  '』',
+	'«':  '»',
+}
+
+// smartQuoteReplacer 把西文弯引号统一为直引号；「」『』等 CJK 引号是正常的标点，不做替换
+var smartQuoteReplacer = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`, "‘", "'", "’", "'", "‚", "'")
+
+// inlineSpacePattern 匹配行内连续的空格和制表符（不包括换行）
+var inlineSpacePattern = regexp.MustCompile(`[ \t\p{Zs}]{2,}`)
+
+// WithNormalizeOutput 设置是否规范化模型输出（默认开启）：
+// 用 DefaultOutputParser 去掉首尾空白、代码块、标签以及包裹整段译文的引号，避免格式差异污染缓存。
+// 通过 WithOutputParser 设置了解析器时此选项不生效
+func WithNormalizeOutput(enabled bool) Option {
+	return func(o *options) {
+		o.normalizeOutput = enabled
+	}
+}
+
+// WithCollapseWhitespace 在规范化输出时把行内连续空白合并为一个空格
+func WithCollapseWhitespace() Option {
+	return func(o *options) {
+		o.collapseWhitespace = true
+	}
+}
+
+// WithUnifyQuotes 在规范化输出时把译文中的西文弯引号统一为直引号（“” -> "，‘’ -> '），
+// 同一句译文不会因为模型这次用了弯引号、下次用了直引号而在缓存和下游比较中被当成不同的结果。
+// 默认关闭：需要保留排版引号的场景（如出版物）不受影响
+func WithUnifyQuotes() Option {
+	return func(o *options) {
+		o.unifyQuotes = true
+	}
+}
+
+// normalizeOutput 去掉首尾空白和包裹整段文本的引号，collapse 为 true 时合并行内连续空白，
+// unifyQuotes 为 true 时把剩下的弯引号统一为直引号
+func normalizeOutput(s string, collapse, unifyQuotes bool) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSpace(stripWrappingQuotes(s))
+	if collapse {
+		s = inlineSpacePattern.ReplaceAllString(s, " ")
+	}
+	if unifyQuotes {
+		s = smartQuoteReplacer.Replace(s)
+	}
+	return s
+}
+
+// stripWrappingQuotes 去掉包裹整段文本的一对引号；内部还有同样的引号时不处理，
+// 以免破坏 `"a" and "b"` 这类本身带引号的译文
+func stripWrappingQuotes(s string) string {
+	first, firstSize := utf8.DecodeRuneInString(s)
+	last, lastSize := utf8.DecodeLastRuneInString(s)
+	if len(s) < firstSize+lastSize {
+		return s
+	}
+	closing, ok := quotePairs[first]
+	if !ok || last != closing {
+		return s
+	}
+	inner := s[firstSize : len(s)-lastSize]
+	if strings.ContainsRune(inner, first) || strings.ContainsRune(inner, closing) {
+		return s
+	}
+	return inner
+}
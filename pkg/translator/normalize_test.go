@@ -0,0 +1,83 @@
+package translator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		collapse bool
+		unify    bool
+		want     string
+	}{
+		{name: "Leading And Trailing Whitespace", input: "  你好，世界 \n", want: "你好，世界"},
+		{name: "Straight Quotes", input: `"你好"`, want: "你好"},
+		{name: "Smart Double Quotes", input: "“你好”", want: "你好"},
+		{name: "Smart Single Quotes", input: "‘hola’", want: "hola"},
+		{name: "Corner Brackets", input: "「こんにちは」", want: "こんにちは"},
+		{name: "Quotes With Inner Whitespace", input: "\" Bonjour \"", want: "Bonjour"},
+		{name: "Inner Quotes Kept", input: `"a" and "b"`, want: `"a" and "b"`},
+		{name: "Unmatched Quote Kept", input: `"Hello`, want: `"Hello`},
+		{name: "Internal Spacing Intact", input: "Hello  world\n\n  second line", want: "Hello  world\n\n  second line"},
+		{name: "Collapse Whitespace", input: "Hello  \t world\n\nsecond   line", collapse: true, want: "Hello world\n\nsecond line"},
+		{name: "Single Quote Char", input: `"`, want: `"`},
+		{name: "Inner Smart Quotes Kept By Default", input: "He said “hi”", want: "He said “hi”"},
+		{name: "Unify Double Quotes", input: "He said “hi” and „bye“", unify: true, want: `He said "hi" and "bye"`},
+		{name: "Unify Single Quotes", input: "It’s ‘fine’", unify: true, want: "It's 'fine'"},
+		{name: "Unify Keeps CJK Quotes", input: "他说「你好」", unify: true, want: "他说「你好」"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeOutput(tt.input, tt.collapse, tt.unify); got != tt.want {
+				t.Errorf("normalizeOutput(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_NormalizeOutput(t *testing.T) {
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": "“你好”"})
+
+	defaultCache.Clear()
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want normalized %q", result, "你好")
+	}
+	if cached, _ := defaultCache.Get("Hello", "English", "Chinese"); cached != "你好" {
+		t.Errorf("cached = %q, want normalized value", cached)
+	}
+
+	defaultCache.Clear()
+	result, err = Translate(ctx, llm, "Hello", "English", "Chinese", WithNormalizeOutput(false))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "“你好”" {
+		t.Errorf("Translate() with normalization off = %q, want raw output", result)
+	}
+}
+
+func TestTranslate_UnifyQuotes(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Say hi": "说“你好”"})
+	ctx := context.Background()
+
+	result, err := Translate(ctx, llm, "Say hi", "English", "Chinese", WithUnifyQuotes())
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if want := `说"你好"`; result != want {
+		t.Errorf("Translate() = %q, want %q", result, want)
+	}
+	if cached, ok := defaultCache.Get("Say hi", "English", "Chinese"); !ok || cached != result {
+		t.Errorf("cached = %q, %v, want the unified result", cached, ok)
+	}
+}
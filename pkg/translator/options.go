@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+)
+
+// Option 用于配置单次翻译调用的可选行为
+type Option func(*options)
+
+// options 保存翻译调用的可选配置
+type options struct {
+	qualityThreshold int            // 质量阈值（0-100），0 表示不做质量评估
+	skipKeys         *regexp.Regexp // JSON 翻译时跳过的键名模式
+
+	historyInCacheKey bool // 对话历史是否参与缓存键
+
+	callbacks callbacks.Handler // chain 和 LLM 调用的回调处理器
+
+	systemPrompt string // 以 system 角色发送的提示词，为空时不发送
+
+	maxTokens int      // 模型单次输出的最大 token 数，0 表示不限制
+	stopWords []string // 模型遇到这些标记时停止输出
+
+	costTracker *CostTracker // 累计 token 用量和费用，为 nil 时不统计
+
+	cacheNormalization CacheNormalization // 计算缓存键前对文本的规范化方式
+
+	batchBudget int // 自动分组批量翻译时每次调用的 token 预算
+
+	splitter Splitter // 长文本翻译时的分段策略，为 nil 时按段落分割
+
+	skipTargetLanguage bool              // 批量翻译前是否跳过已经是目标语言的文本
+	detectCache        *TranslationCache // 语言检测结果的缓存，为 nil 时使用 detectCache
+	failureFallback    FailureFallback   // 批量翻译中单条失败时的处理方式
+
+	normalizeOutput    bool // 是否规范化模型输出
+	collapseWhitespace bool // 规范化时是否合并内部连续空白
+	unifyQuotes        bool // 规范化时是否把弯引号统一为直引号
+
+	outputParser OutputParser // 从模型回复中提取译文的解析器，为 nil 时使用 DefaultOutputParser
+
+	scriptCheck bool // 翻译前是否检查输入的书写系统与源语言是否相符
+
+	hint string // 针对有歧义词语的提示，附加在翻译提示词之后
+
+	cacheNamespace string // 计入缓存键的命名空间，用于让旧条目失效
+
+	strictOutput bool // 是否对译文做第二次校验，清理附带的解释
+
+	fuzzyThreshold float64        // 模糊缓存匹配的相似度阈值，0 表示不开启
+	similarity     SimilarityFunc // 模糊缓存匹配的相似度函数，为 nil 时使用 LevenshteinSimilarity
+
+	romanization bool // 批量翻译结果是否附带译文的罗马字转写
+
+	timeout time.Duration // 单次翻译（含所有重试）的总超时，0 表示只受调用方 context 约束
+
+	strictConcurrencyChecks bool // 批量翻译结束后是否检查信号量和工作 goroutine 全部释放
+
+	postEdit PostEditFunc // 译文写入缓存和返回前的确定性后处理，为 nil 时不处理
+
+	noCache bool // 是否跳过共享缓存的读写
+
+	echoGuard   bool // 跨语言翻译的输出与原文相同时是否视为失败
+	echoRetries int  // 回显时用严格指令重试的次数
+
+	injectionGuard bool // 输入疑似提示注入时是否改用分隔用户文本的模板
+
+	mockFallback     bool         // 未配置模型或认证失败时是否返回模拟译文
+	mockFallbackUsed *atomic.Bool // 使用了模拟译文时置为 true，可以为 nil
+
+	retryTemperature      bool    // 重试时是否逐次提高采样温度
+	retryTemperatureStart float64 // 第一次重试的温度
+	retryTemperatureStep  float64 // 每次重试增加的温度
+	retryTemperatureMax   float64 // 温度上限
+
+	model string // 本次调用使用的模型，为空时使用 context 中的覆盖值或客户端的默认模型
+
+	instructionLanguage InstructionLanguage // "只输出译文" 指令使用的语言
+	outputInstruction   string              // 自定义的 "只输出译文" 指令，优先于 instructionLanguage
+
+	forceTranslate bool // 是否翻译只含网址、邮箱或代码的文本
+
+	normalizeUnicode bool // 翻译前是否把输入规范化为 Unicode NFC
+}
+
+// newOptions 根据传入的 Option 构建配置
+func newOptions(opts []Option) *options {
+	o := &options{
+		batchBudget:      defaultBatchBudget,
+		normalizeOutput:  true,
+		normalizeUnicode: true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
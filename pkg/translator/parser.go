@@ -0,0 +1,93 @@
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// OutputParser 从模型的原始回复中提取译文。
+// 不同模型包装输出的方式不同（引号、代码块、"Translation:" 前缀等），可按需替换
+type OutputParser interface {
+	Parse(raw string) (string, error)
+}
+
+// OutputParserFunc 让普通函数实现 OutputParser 接口
+type OutputParserFunc func(raw string) (string, error)
+
+// Parse 调用 f(raw)
+func (f OutputParserFunc) Parse(raw string) (string, error) {
+	return f(raw)
+}
+
+// codeFencePattern 匹配包裹整段回复的 Markdown 代码块，语言标记可选
+var codeFencePattern = regexp.MustCompile("(?s)^```[\\w-]*[ \\t]*\\n(.*?)\\n?```$")
+
+// labelPattern 匹配模型加在译文前的标签，如 "Translation:"、"Translated text:"、"译文："
+var labelPattern = regexp.MustCompile(`(?i)^(?:translation|translated text|译文|翻译|翻译结果)\s*[:：]\s*`)
+
+// wrappedLabelPattern 匹配 "Output:"、"Result:" 这类通用标签。它们也可能是原文本身的内容，
+// 所以只有标签独占一行、后面紧跟引号或代码块包裹的内容时才视为模型加的标签
+var wrappedLabelPattern = regexp.MustCompile(`(?is)^(?:output|result)\s*[:：][ \t]*\n\s*(.*)$`)
+
+// DefaultOutputParser 处理常见的包装方式：首尾空白、代码块、译文标签和包裹整段译文的引号。
+// 它不会返回错误
+type DefaultOutputParser struct {
+	// CollapseWhitespace 为 true 时把行内连续空白合并为一个空格
+	CollapseWhitespace bool
+	// UnifyQuotes 为 true 时把西文弯引号统一为直引号
+	UnifyQuotes bool
+}
+
+// Parse 依次去掉代码块、标签和引号，返回清理后的译文
+func (p DefaultOutputParser) Parse(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if m := codeFencePattern.FindStringSubmatch(s); m != nil {
+		s = strings.TrimSpace(m[1])
+	}
+	s = labelPattern.ReplaceAllString(s, "")
+	s = stripWrappedLabel(s)
+	return normalizeOutput(s, p.CollapseWhitespace, p.UnifyQuotes), nil
+}
+
+// stripWrappedLabel 去掉独占一行的通用标签，并展开其后包裹整段内容的代码块；
+// 标签后的内容没有被引号或代码块包裹时原样返回
+func stripWrappedLabel(s string) string {
+	m := wrappedLabelPattern.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	rest := m[1]
+	if fence := codeFencePattern.FindStringSubmatch(rest); fence != nil {
+		return strings.TrimSpace(fence[1])
+	}
+	first, _ := utf8.DecodeRuneInString(rest)
+	if _, ok := quotePairs[first]; ok && stripWrappingQuotes(rest) != rest {
+		return rest
+	}
+	return s
+}
+
+// WithOutputParser 设置从模型回复中提取译文的解析器，替换默认的 DefaultOutputParser
+func WithOutputParser(p OutputParser) Option {
+	return func(o *options) {
+		o.outputParser = p
+	}
+}
+
+// parse 用配置的解析器处理模型输出；未设置解析器且关闭了规范化时原样返回
+func (o *options) parse(out string) (string, error) {
+	parser := o.outputParser
+	if parser == nil {
+		if !o.normalizeOutput {
+			return out, nil
+		}
+		parser = DefaultOutputParser{CollapseWhitespace: o.collapseWhitespace, UnifyQuotes: o.unifyQuotes}
+	}
+	result, err := parser.Parse(out)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse translation output: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultOutputParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		collapse bool
+		want     string
+	}{
+		{name: "Plain", input: "你好，世界", want: "你好，世界"},
+		{name: "Whitespace", input: "\n  你好  \n", want: "你好"},
+		{name: "Quotes", input: "“你好”", want: "你好"},
+		{name: "Code Fence", input: "```\n你好\n```", want: "你好"},
+		{name: "Code Fence With Language", input: "```text\n你好，世界\n```", want: "你好，世界"},
+		{name: "Multiline Code Fence", input: "```\n第一行\n第二行\n```", want: "第一行\n第二行"},
+		{name: "English Label", input: "Translation: 你好", want: "你好"},
+		{name: "Label Case Insensitive", input: "translated text: Bonjour", want: "Bonjour"},
+		{name: "Chinese Label", input: "译文：Hello", want: "Hello"},
+		{name: "Label And Quotes", input: `Translation: "你好"`, want: "你好"},
+		{name: "Fence And Label", input: "```\nTranslation: 你好\n```", want: "你好"},
+		{name: "Label Mid Text Kept", input: "The translation: is fine", want: "The translation: is fine"},
+		{name: "Inline Backticks Kept", input: "use `go test`", want: "use `go test`"},
+		{name: "Output Label Before Quotes", input: "Output:\n\"你好\"", want: "你好"},
+		{name: "Result Label Before Fence", input: "Result:\n```\n你好\n```", want: "你好"},
+		{name: "Output Label Inline Kept", input: "Output: 42 units", want: "Output: 42 units"},
+		{name: "Result Label Inline Quotes Kept", input: `Result: "ok"`, want: `Result: "ok"`},
+		{name: "Result Label Plain Next Line Kept", input: "Result:\nthe build passed", want: "Result:\nthe build passed"},
+		{name: "Collapse Whitespace", input: "Translation: a   b", collapse: true, want: "a b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultOutputParser{CollapseWhitespace: tt.collapse}.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_DefaultOutputParser(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "```\nTranslation: 你好\n```"})
+
+	result, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+}
+
+func TestTranslate_CustomOutputParser(t *testing.T) {
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": "<t>你好</t>", "Bye": "再见"})
+
+	// 自定义解析器：只接受 <t>...</t> 包裹的回复
+	parser := OutputParserFunc(func(raw string) (string, error) {
+		inner, ok := strings.CutPrefix(raw, "<t>")
+		if !ok {
+			return "", errors.New("missing <t> tag")
+		}
+		inner, ok = strings.CutSuffix(inner, "</t>")
+		if !ok {
+			return "", errors.New("missing </t> tag")
+		}
+		return inner, nil
+	})
+
+	defaultCache.Clear()
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithOutputParser(parser))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+
+	// 解析失败时返回错误，且不写入缓存
+	_, err = Translate(ctx, llm, "Bye", "English", "Chinese", WithOutputParser(parser))
+	if err == nil || !strings.Contains(err.Error(), "missing <t> tag") {
+		t.Errorf("Translate() error = %v, want parser error", err)
+	}
+	if _, ok := defaultCache.Get("Bye", "English", "Chinese"); ok {
+		t.Error("failed parse should not be cached")
+	}
+}
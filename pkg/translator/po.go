@@ -0,0 +1,179 @@
+package translator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// poField 是 .po 条目中的一个字段（msgid、msgstr[0] 等），可以跨越多行
+type poField struct {
+	name  string
+	start int // 起始行（包含）
+	end   int // 结束行（不包含）
+	value string
+}
+
+// poEntry 是 .po 文件中的一个翻译条目
+type poEntry struct {
+	fields []*poField
+}
+
+func (e *poEntry) field(name string) *poField {
+	for _, f := range e.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// TranslatePO 读取 gettext .po 文件，把未翻译条目的 msgid 翻译后写入 msgstr，
+// 并将结果写入 w。注释、头部条目和已有的翻译原样保留；force 为 true 时重新翻译所有条目。
+// 返回被填充的条目数。
+func TranslatePO(ctx context.Context, llm llms.Model, r io.Reader, w io.Writer, inputLanguage string, outputLanguage string, force bool) (int, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read po file: %w", err)
+	}
+
+	entries, err := parsePO(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	// 收集需要翻译的 msgstr 字段及其原文
+	replacements := make(map[int]*poField) // 起始行 -> 需要替换的字段
+	sources := make(map[*poField]string)
+	var texts []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		msgid := e.field("msgid")
+		if msgid == nil || msgid.value == "" {
+			// 头部条目不翻译
+			continue
+		}
+		for _, f := range e.fields {
+			if !strings.HasPrefix(f.name, "msgstr") || (f.value != "" && !force) {
+				continue
+			}
+			source := msgid.value
+			if plural := e.field("msgid_plural"); plural != nil && f.name != "msgstr[0]" {
+				source = plural.value
+			}
+			replacements[f.start] = f
+			sources[f] = source
+			if !seen[source] {
+				seen[source] = true
+				texts = append(texts, source)
+			}
+		}
+	}
+
+	translations := make(map[string]string, len(texts))
+	if len(texts) > 0 {
+		results, err := TranslateBatch(ctx, llm, texts, inputLanguage, outputLanguage)
+		if err != nil {
+			return 0, err
+		}
+		for i, text := range texts {
+			translations[text] = results[i]
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(lines); i++ {
+		if f, ok := replacements[i]; ok {
+			fmt.Fprintf(bw, "%s %s\n", f.name, quotePO(translations[sources[f]]))
+			i = f.end - 1
+			continue
+		}
+		fmt.Fprintln(bw, lines[i])
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write po file: %w", err)
+	}
+	return len(replacements), nil
+}
+
+// parsePO 把 .po 文件的行解析为条目，记录每个字段所在的行范围
+func parsePO(lines []string) ([]*poEntry, error) {
+	var entries []*poEntry
+	var entry *poEntry
+	var current *poField
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case strings.HasPrefix(line, `"`) && current != nil:
+			// 续行
+			value, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current.value += value
+			current.end = i + 1
+		case line == "" || strings.HasPrefix(line, "#"):
+			// 空行和注释结束当前字段，空行同时结束当前条目
+			current = nil
+			if line == "" {
+				entry = nil
+			}
+		default:
+			name, quoted, ok := strings.Cut(line, " ")
+			if !ok || !isPOKeyword(name) {
+				return nil, fmt.Errorf("line %d: unexpected content %q", i+1, line)
+			}
+			value, err := unquotePO(strings.TrimSpace(quoted))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			// msgctxt 或 msgid 出现在已有 msgid 的条目中时，开始新条目
+			if entry == nil || ((name == "msgctxt" || name == "msgid") && entry.field("msgid") != nil) {
+				entry = &poEntry{}
+				entries = append(entries, entry)
+			}
+			current = &poField{name: name, start: i, end: i + 1, value: value}
+			entry.fields = append(entry.fields, current)
+		}
+	}
+	return entries, nil
+}
+
+func isPOKeyword(name string) bool {
+	switch name {
+	case "msgctxt", "msgid", "msgid_plural", "msgstr":
+		return true
+	}
+	return strings.HasPrefix(name, "msgstr[") && strings.HasSuffix(name, "]")
+}
+
+func unquotePO(s string) (string, error) {
+	value, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted string %s", s)
+	}
+	return value, nil
+}
+
+func quotePO(s string) string {
+	return strconv.Quote(s)
+}
+
+// readLines 读取所有行（不含换行符）
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
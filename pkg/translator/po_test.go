@@ -0,0 +1,129 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const poFixture = `# Translation file for demo
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: zh\n"
+
+#: main.go:10
+msgid "Hello world"
+msgstr ""
+
+# translator note: keep existing
+#: main.go:12
+msgid "Thank you"
+msgstr "多谢"
+
+#, c-format
+msgid ""
+"Good "
+"morning"
+msgstr ""
+
+msgid "One file"
+msgid_plural "Many files"
+msgstr[0] ""
+msgstr[1] ""
+`
+
+func withoutBatchDelay(t *testing.T) {
+	t.Helper()
+	oldItem, oldBatch := itemDelay, batchDelay
+	itemDelay, batchDelay = 0, 0
+	t.Cleanup(func() { itemDelay, batchDelay = oldItem, oldBatch })
+}
+
+func TestTranslatePO(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world":  "你好，世界",
+		"Thank you":    "谢谢",
+		"Good morning": "早上好",
+		"One file":     "一个文件",
+		"Many files":   "多个文件",
+	})
+
+	var out strings.Builder
+	n, err := TranslatePO(context.Background(), llm, strings.NewReader(poFixture), &out, "English", "Chinese", false)
+	if err != nil {
+		t.Fatalf("TranslatePO() error = %v", err)
+	}
+	if n != 4 {
+		t.Errorf("TranslatePO() filled %d entries, want 4", n)
+	}
+
+	want := `# Translation file for demo
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: zh\n"
+
+#: main.go:10
+msgid "Hello world"
+msgstr "你好，世界"
+
+# translator note: keep existing
+#: main.go:12
+msgid "Thank you"
+msgstr "多谢"
+
+#, c-format
+msgid ""
+"Good "
+"morning"
+msgstr "早上好"
+
+msgid "One file"
+msgid_plural "Many files"
+msgstr[0] "一个文件"
+msgstr[1] "多个文件"
+`
+	if out.String() != want {
+		t.Errorf("TranslatePO() output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTranslatePO_Force(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world":  "你好，世界",
+		"Thank you":    "谢谢",
+		"Good morning": "早上好",
+		"One file":     "一个文件",
+		"Many files":   "多个文件",
+	})
+
+	var out strings.Builder
+	n, err := TranslatePO(context.Background(), llm, strings.NewReader(poFixture), &out, "English", "Chinese", true)
+	if err != nil {
+		t.Fatalf("TranslatePO() error = %v", err)
+	}
+	if n != 5 {
+		t.Errorf("TranslatePO() filled %d entries, want 5", n)
+	}
+	if !strings.Contains(out.String(), `msgstr "谢谢"`) {
+		t.Error("force should re-translate existing entries")
+	}
+	// 头部条目始终保留
+	if !strings.Contains(out.String(), `"Language: zh\n"`) {
+		t.Error("header entry should be preserved")
+	}
+}
+
+func TestTranslatePO_Malformed(t *testing.T) {
+	llm := newDictLLM(nil)
+	var out strings.Builder
+	_, err := TranslatePO(context.Background(), llm, strings.NewReader("msgid \"Hello\nmsgstr \"\"\n"), &out, "English", "Chinese", false)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected error mentioning line 1, got: %v", err)
+	}
+}
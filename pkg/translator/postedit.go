@@ -0,0 +1,21 @@
+package translator
+
+// PostEditFunc 对译文做确定性的后处理，参数依次为译文、源语言和目标语言，返回处理后的译文
+type PostEditFunc func(target, inputLanguage, outputLanguage string) string
+
+// WithPostEdit 设置译后编辑函数，用于落实团队的排版规范，
+// 例如把直引号换成目标语言的引号、调整中日文标点周围的空格。
+// 函数在译文写入缓存和返回之前执行，缓存中保存的是处理后的译文，缓存命中时不会重复处理
+func WithPostEdit(f PostEditFunc) Option {
+	return func(o *options) {
+		o.postEdit = f
+	}
+}
+
+// postEditText 在设置了译后编辑函数时处理译文
+func (o *options) postEditText(target, inputLanguage, outputLanguage string) string {
+	if o.postEdit == nil {
+		return target
+	}
+	return o.postEdit(target, inputLanguage, outputLanguage)
+}
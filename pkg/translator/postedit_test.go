@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslate_PostEdit(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Good night": "bonne nuit"})
+
+	edits := 0
+	upper := WithPostEdit(func(target, inputLanguage, outputLanguage string) string {
+		edits++
+		if inputLanguage != "English" || outputLanguage != "French" {
+			t.Errorf("post-edit languages = %s -> %s, want English -> French", inputLanguage, outputLanguage)
+		}
+		return strings.ToUpper(target)
+	})
+
+	got, err := Translate(ctx, llm, "Good night", "English", "French", upper)
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "BONNE NUIT" {
+		t.Errorf("Translate() = %q, want %q", got, "BONNE NUIT")
+	}
+
+	// 缓存中保存的是处理后的译文，命中时不再调用模型，也不重复处理
+	if cached, _ := defaultCache.Get("Good night", "English", "French"); cached != "BONNE NUIT" {
+		t.Errorf("cached = %q, want %q", cached, "BONNE NUIT")
+	}
+	if got, _ := Translate(ctx, llm, "Good night", "English", "French", upper); got != "BONNE NUIT" {
+		t.Errorf("Translate() on cache hit = %q, want %q", got, "BONNE NUIT")
+	}
+	if llm.Calls() != 1 || edits != 1 {
+		t.Errorf("LLM calls = %d, post-edits = %d, want 1 and 1", llm.Calls(), edits)
+	}
+}
+
+func TestTranslateBatchCombined_PostEdit(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) { return "1. 你好\n2. 谢谢", nil }}
+
+	// 团队规范：中文译文末尾统一加句号
+	period := WithPostEdit(func(target, inputLanguage, outputLanguage string) string {
+		return target + "。"
+	})
+	got, err := TranslateBatchCombined(context.Background(), llm, []string{"Hello", "Thanks"}, "English", "Chinese", period)
+	if err != nil {
+		t.Fatalf("TranslateBatchCombined() error = %v", err)
+	}
+	if got[0] != "你好。" || got[1] != "谢谢。" {
+		t.Errorf("TranslateBatchCombined() = %q, want post-edited translations", got)
+	}
+	if cached, _ := defaultCache.Get("Thanks", "English", "Chinese"); cached != "谢谢。" {
+		t.Errorf("cached = %q, want %q", cached, "谢谢。")
+	}
+}
@@ -0,0 +1,133 @@
+package translator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// PreloadItem 是需要预热进缓存的一条文本及其语言对
+type PreloadItem struct {
+	Text string // 原文
+	In   string // 源语言
+	Out  string // 目标语言
+}
+
+// PreloadCache 并发翻译 items 并写入缓存，用于服务启动时预热常用文案。
+// 并发数和调用间隔与批量翻译相同；返回的切片与 items 一一对应，成功的条目为 nil
+func PreloadCache(ctx context.Context, llm llms.Model, items []PreloadItem, opts ...Option) []error {
+	errs := make([]error, len(items))
+	var wg sync.WaitGroup
+
+	// 限制并发数
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	for i, item := range items {
+		wg.Add(1)
+		go func(index int, item PreloadItem) {
+			defer wg.Done()
+
+			// 获取信号量
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := ctx.Err(); err != nil {
+				errs[index] = err
+				return
+			}
+
+			// 为每个翻译任务设置独立的超时
+			taskCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+			defer cancel()
+
+			if _, err := Translate(taskCtx, llm, item.Text, item.In, item.Out, opts...); err != nil {
+				errs[index] = fmt.Errorf("failed to preload item %d: %w", index, err)
+				return
+			}
+
+			// 添加延迟以避免 API 限制
+			time.Sleep(itemDelay)
+		}(i, item)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// rankedPhrase 是词频文件中的一条短语及其累计次数
+type rankedPhrase struct {
+	text  string
+	count int
+}
+
+// WarmFromFile 读取 path 中的高频短语，按频次从高到低取前 topN 条预热进缓存。
+// 每行是一条短语，也可以写成 "次数\t短语"；重复出现的短语次数累加，频次相同时保持文件中的先后顺序。
+// topN <= 0 时预热全部短语；单条翻译失败不会中断其余条目，所有错误合并后返回
+func WarmFromFile(ctx context.Context, llm llms.Model, path string, in, out string, topN int, opts ...Option) error {
+	phrases, err := readRankedPhrases(path)
+	if err != nil {
+		return err
+	}
+	if topN > 0 && len(phrases) > topN {
+		phrases = phrases[:topN]
+	}
+
+	items := make([]PreloadItem, len(phrases))
+	for i, p := range phrases {
+		items[i] = PreloadItem{Text: p.text, In: in, Out: out}
+	}
+	return errors.Join(PreloadCache(ctx, llm, items, opts...)...)
+}
+
+// readRankedPhrases 解析词频文件并按频次降序返回短语，空行被忽略
+func readRankedPhrases(path string) ([]rankedPhrase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open phrase file: %w", err)
+	}
+	defer f.Close()
+
+	var phrases []rankedPhrase
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		text, count := parseRankedLine(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if i, ok := index[text]; ok {
+			phrases[i].count += count
+			continue
+		}
+		index[text] = len(phrases)
+		phrases = append(phrases, rankedPhrase{text: text, count: count})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read phrase file: %w", err)
+	}
+
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return phrases[i].count > phrases[j].count
+	})
+	return phrases, nil
+}
+
+// parseRankedLine 解析 "次数\t短语" 格式的一行，没有合法次数前缀时整行视为出现一次的短语
+func parseRankedLine(line string) (string, int) {
+	line = strings.TrimSpace(line)
+	if prefix, rest, ok := strings.Cut(line, "\t"); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(prefix)); err == nil && n >= 0 {
+			return strings.TrimSpace(rest), n
+		}
+	}
+	return line, 1
+}
@@ -0,0 +1,121 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreloadCache(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Sign in":  "登录",
+		"Sign out": "退出登录",
+		"Settings": "设置",
+	})
+
+	items := []PreloadItem{
+		{Text: "Sign in", In: "English", Out: "Chinese"},
+		{Text: "Sign out", In: "English", Out: "Chinese"},
+		{Text: "Settings", In: "English", Out: "Chinese"},
+	}
+	for i, err := range PreloadCache(context.Background(), llm, items) {
+		if err != nil {
+			t.Errorf("PreloadCache() item %d error = %v", i, err)
+		}
+	}
+
+	want := []string{"登录", "退出登录", "设置"}
+	for i, item := range items {
+		if got, ok := defaultCache.Get(item.Text, item.In, item.Out); !ok || got != want[i] {
+			t.Errorf("cache for %q = %q, %v, want hit %q", item.Text, got, ok, want[i])
+		}
+	}
+
+	// 预热后再次翻译全部命中缓存
+	calls := llm.Calls()
+	for _, item := range items {
+		if _, err := Translate(context.Background(), llm, item.Text, item.In, item.Out); err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+	}
+	if llm.Calls() != calls {
+		t.Errorf("expected no LLM calls after preload, got %d more", llm.Calls()-calls)
+	}
+}
+
+func TestPreloadCache_PerItemErrors(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Sign in": "登录"})
+
+	errs := PreloadCache(context.Background(), llm, []PreloadItem{
+		{Text: "Sign in", In: "English", Out: "Chinese"},
+		{Text: "", In: "English", Out: "Chinese"},
+		{Text: "Unknown", In: "English", Out: "Chinese"},
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(errs))
+	}
+	if errs[0] != nil {
+		t.Errorf("item 0 error = %v, want nil", errs[0])
+	}
+	if !errors.Is(errs[1], ErrEmptyText) {
+		t.Errorf("item 1 error = %v, want ErrEmptyText", errs[1])
+	}
+	if errs[2] == nil {
+		t.Error("expected error for item 2")
+	}
+}
+
+func TestWarmFromFile(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Sign in":  "登录",
+		"Sign out": "退出登录",
+		"Settings": "设置",
+		"Help":     "帮助",
+	})
+
+	path := filepath.Join(t.TempDir(), "phrases.tsv")
+	content := "3\tSettings\n" +
+		"Help\n" +
+		"\n" +
+		"12\tSign in\n" +
+		"5\tSign out\n" +
+		"Settings\n" +
+		"Settings\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign in(12) > Settings(3+1+1) = Sign out(5) > Help(1)，频次相同时 Settings 在文件中更靠前
+	if err := WarmFromFile(context.Background(), llm, path, "English", "Chinese", 2); err != nil {
+		t.Fatalf("WarmFromFile() error = %v", err)
+	}
+
+	for text, want := range map[string]string{"Sign in": "登录", "Settings": "设置"} {
+		if got, ok := defaultCache.Get(text, "English", "Chinese"); !ok || got != want {
+			t.Errorf("cache for %q = %q, %v, want hit %q", text, got, ok, want)
+		}
+	}
+	for _, text := range []string{"Sign out", "Help"} {
+		if _, ok := defaultCache.Get(text, "English", "Chinese"); ok {
+			t.Errorf("lower-ranked %q should not be preloaded", text)
+		}
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times, want 2", llm.Calls())
+	}
+}
+
+func TestWarmFromFile_MissingFile(t *testing.T) {
+	err := WarmFromFile(context.Background(), newDictLLM(nil), filepath.Join(t.TempDir(), "missing.tsv"), "English", "Chinese", 10)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("WarmFromFile() error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,93 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// qualityRetries 是译文质量低于阈值时的重新翻译次数
+const qualityRetries = 1
+
+var scorePattern = regexp.MustCompile(`-?\d+`)
+
+// WithQualityThreshold 设置译文质量阈值（0-100）。
+// 评分低于阈值时会重新翻译，仍不达标则返回译文并附带 ErrLowQuality。
+func WithQualityThreshold(n int) Option {
+	return func(o *options) {
+		o.qualityThreshold = n
+	}
+}
+
+// EstimateQuality 让模型对译文的忠实度打分，返回 0-100 之间的整数
+func EstimateQuality(ctx context.Context, llm llms.Model, source, translation string, inputLanguage string, outputLanguage string, opts ...Option) (int, error) {
+	return estimateQuality(ctx, llm, source, translation, inputLanguage, outputLanguage, newOptions(opts))
+}
+
+func estimateQuality(ctx context.Context, llm llms.Model, source, translation string, inputLanguage string, outputLanguage string, o *options) (int, error) {
+	if source == "" || translation == "" {
+		return 0, ErrEmptyText
+	}
+
+	reply, err := runPrompt(ctx, llm, o,
+		`Rate how faithfully the translation from {{.inputLanguage}} to {{.outputLanguage}} preserves the meaning of the source, on a scale of 0 to 100. Reply with the number only.
+Source: {{.source}}
+Translation: {{.translation}}`,
+		map[string]any{
+			"inputLanguage":  inputLanguage,
+			"outputLanguage": outputLanguage,
+			"source":         source,
+			"translation":    translation,
+		})
+	if err != nil {
+		return 0, fmt.Errorf("quality estimation failed: %w", err)
+	}
+	return parseQualityScore(reply)
+}
+
+// parseQualityScore 从模型回复中解析出第一个整数并限制在 0-100 范围内，
+// 兼容 "85"、"Score: 85/100"、"85%" 等格式
+func parseQualityScore(reply string) (int, error) {
+	match := scorePattern.FindString(reply)
+	if match == "" {
+		return 0, fmt.Errorf("no score found in reply: %q", reply)
+	}
+	score, err := strconv.Atoi(match)
+	if err != nil {
+		return 0, fmt.Errorf("invalid score %q: %w", match, err)
+	}
+	if score < 0 {
+		score = 0
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, nil
+}
+
+// ensureQuality 评估译文质量，低于阈值时重新翻译
+func ensureQuality(ctx context.Context, llm llms.Model, text, translation string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	threshold := o.qualityThreshold
+	for attempt := 0; ; attempt++ {
+		score, err := estimateQuality(ctx, llm, text, translation, inputLanguage, outputLanguage, o)
+		if err != nil {
+			return translation, err
+		}
+		if score >= threshold {
+			return translation, nil
+		}
+		if attempt >= qualityRetries || !SpendRetry(ctx) {
+			return translation, fmt.Errorf("%w: score %d < %d", ErrLowQuality, score, threshold)
+		}
+
+		log.Printf("Translation quality %d below threshold %d, retrying", score, threshold)
+		translation, err = translateOnce(ctx, llm, text, inputLanguage, outputLanguage, o)
+		if err != nil {
+			return "", err
+		}
+	}
+}
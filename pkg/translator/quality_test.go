@@ -0,0 +1,106 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseQualityScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		want      int
+		wantError bool
+	}{
+		{name: "Plain Number", reply: "85", want: 85},
+		{name: "Score Prefix", reply: "Score: 85/100", want: 85},
+		{name: "Percent", reply: "92%", want: 92},
+		{name: "Surrounding Whitespace", reply: "  70\n", want: 70},
+		{name: "Sentence", reply: "I would rate this translation 60 out of 100.", want: 60},
+		{name: "Clamp High", reply: "150", want: 100},
+		{name: "Clamp Low", reply: "-5", want: 0},
+		{name: "No Number", reply: "excellent", wantError: true},
+		{name: "Empty", reply: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQualityScore(tt.reply)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("parseQualityScore(%q) error = %v, wantError %v", tt.reply, err, tt.wantError)
+			}
+			if !tt.wantError && got != tt.want {
+				t.Errorf("parseQualityScore(%q) = %d, want %d", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateQuality(t *testing.T) {
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Score: 88/100", nil
+	}}
+
+	score, err := EstimateQuality(context.Background(), llm, "Hello", "你好", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("EstimateQuality() error = %v", err)
+	}
+	if score != 88 {
+		t.Errorf("EstimateQuality() = %d, want 88", score)
+	}
+	if !strings.Contains(llm.prompts[0], "Hello") || !strings.Contains(llm.prompts[0], "你好") {
+		t.Errorf("prompt missing source or translation: %s", llm.prompts[0])
+	}
+}
+
+func TestTranslate_QualityThreshold(t *testing.T) {
+	defaultCache.Clear()
+
+	// 第一次译文评分低，重新翻译后评分达标
+	translations := []string{"坏的翻译", "你好"}
+	scores := map[string]string{"坏的翻译": "20", "你好": "95"}
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.HasPrefix(prompt, "Rate") {
+			for translation, score := range scores {
+				if strings.Contains(prompt, "Translation: "+translation) {
+					return score, nil
+				}
+			}
+			return "0", nil
+		}
+		out := translations[0]
+		translations = translations[1:]
+		return out, nil
+	}}
+
+	result, err := Translate(context.Background(), llm, "Hello", "English", "Chinese", WithQualityThreshold(80))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+}
+
+func TestTranslate_QualityThresholdNotMet(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.HasPrefix(prompt, "Rate") {
+			return "10", nil
+		}
+		return "坏的翻译", nil
+	}}
+
+	result, err := Translate(context.Background(), llm, "Hello", "English", "Chinese", WithQualityThreshold(80))
+	if !errors.Is(err, ErrLowQuality) {
+		t.Fatalf("expected ErrLowQuality, got: %v", err)
+	}
+	if result != "坏的翻译" {
+		t.Errorf("expected flagged translation to be returned, got %q", result)
+	}
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("low quality translation should not be cached")
+	}
+}
@@ -0,0 +1,45 @@
+package translator
+
+import (
+	"strings"
+	"sync"
+)
+
+// defaultPromptTemplate 是未注册专用模板的语言对使用的翻译模板
+const defaultPromptTemplate = `Translate "{{.text}}" from {{.inputLanguage}} to {{.outputLanguage}}. ` + defaultOutputInstruction
+
+// promptRegistry 保存按语言对注册的翻译模板
+var promptRegistry = struct {
+	mu        sync.RWMutex
+	templates map[string]string
+}{templates: make(map[string]string)}
+
+// RegisterPrompt 为指定语言对注册专用的翻译模板，Translate 会优先使用它而不是默认模板。
+// 模板使用 Go template 语法，可引用 {{.text}}、{{.inputLanguage}} 和 {{.outputLanguage}}；
+// 语言名称不区分大小写和首尾空白。template 为空时删除已注册的模板
+func RegisterPrompt(inputLang, outputLang, template string) {
+	key := languagePairKey(inputLang, outputLang)
+
+	promptRegistry.mu.Lock()
+	defer promptRegistry.mu.Unlock()
+	if template == "" {
+		delete(promptRegistry.templates, key)
+		return
+	}
+	promptRegistry.templates[key] = template
+}
+
+// promptFor 返回语言对对应的翻译模板，未注册时返回默认模板
+func promptFor(inputLang, outputLang string) string {
+	promptRegistry.mu.RLock()
+	defer promptRegistry.mu.RUnlock()
+	if template, ok := promptRegistry.templates[languagePairKey(inputLang, outputLang)]; ok {
+		return template
+	}
+	return defaultPromptTemplate
+}
+
+// languagePairKey 生成规范化的语言对键
+func languagePairKey(inputLang, outputLang string) string {
+	return strings.ToLower(strings.TrimSpace(inputLang)) + "->" + strings.ToLower(strings.TrimSpace(outputLang))
+}
@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegisterPrompt(t *testing.T) {
+	defaultCache.Clear()
+	RegisterPrompt(" english", "Chinese ", `Translate "{{.text}}" into {{.outputLanguage}}. Use simplified characters only.`)
+	t.Cleanup(func() { RegisterPrompt("English", "Chinese", "") })
+
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	ctx := context.Background()
+
+	if _, err := Translate(ctx, llm, "Hello", "ENGLISH", "chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if !strings.Contains(llm.prompts[0], "Use simplified characters only.") {
+		t.Errorf("expected registered template for English->Chinese, got prompt: %s", llm.prompts[0])
+	}
+
+	// 其他语言对仍使用默认模板
+	if _, err := Translate(ctx, llm, "Hello", "English", "Japanese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if strings.Contains(llm.prompts[1], "simplified characters") {
+		t.Errorf("registered template leaked to English->Japanese: %s", llm.prompts[1])
+	}
+	if !strings.Contains(llm.prompts[1], "Output the translation only") {
+		t.Errorf("expected default template for English->Japanese, got prompt: %s", llm.prompts[1])
+	}
+
+	// 反方向是不同的语言对
+	if got := promptFor("Chinese", "English"); got != defaultPromptTemplate {
+		t.Errorf("promptFor(Chinese, English) = %q, want default template", got)
+	}
+}
+
+func TestRegisterPrompt_EmptyRemoves(t *testing.T) {
+	RegisterPrompt("English", "French", "Custom {{.text}}")
+	RegisterPrompt("English", "French", "")
+	if got := promptFor("English", "French"); got != defaultPromptTemplate {
+		t.Errorf("promptFor() after removal = %q, want default template", got)
+	}
+}
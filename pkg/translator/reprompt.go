@@ -0,0 +1,127 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// metaPhrases 是模型在译文前后添加说明时常见的措辞（小写）
+var metaPhrases = []string{
+	"here is the translation",
+	"here's the translation",
+	"the translation is",
+	"translated text:",
+	"translation:",
+	"i translated",
+	"以下是翻译",
+	"翻译如下",
+	"译文：",
+}
+
+// isSuspiciousOutput 判断译文是否可疑：跨语言翻译却原样返回了原文，或包含说明性措辞
+func isSuspiciousOutput(text, out string, inputLanguage string, outputLanguage string) bool {
+	if isEcho(text, out, inputLanguage, outputLanguage) {
+		return true
+	}
+
+	lower := strings.ToLower(out)
+	for _, phrase := range metaPhrases {
+		// 原文本身包含该措辞时不算可疑
+		if strings.Contains(lower, phrase) && !strings.Contains(strings.ToLower(text), phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEcho 判断跨语言翻译的输出是否只是原文的回显：去掉包裹的引号并合并空白后与原文完全相同
+func isEcho(text, out string, inputLanguage string, outputLanguage string) bool {
+	if strings.EqualFold(strings.TrimSpace(inputLanguage), strings.TrimSpace(outputLanguage)) {
+		return false
+	}
+	return normalizeEcho(out) == normalizeEcho(text)
+}
+
+// normalizeEcho 去掉首尾空白和引号，并把连续空白合并为一个空格
+func normalizeEcho(s string) string {
+	return strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(s), `"'“”‘’`)), " ")
+}
+
+// WithEchoGuard 把跨语言翻译时模型原样返回原文的情况视为失败：用更严格的指令最多重试 retries 次，
+// 仍然回显则返回 ErrEcho，且不写入缓存。默认只重新提示一次，仍回显时返回该输出但不缓存。
+// 品牌名、代码等本来就不需要翻译的文本在开启后也会得到 ErrEcho
+func WithEchoGuard(retries int) Option {
+	return func(o *options) {
+		o.echoGuard = true
+		o.echoRetries = max(retries, 0)
+	}
+}
+
+// WithRetryTemperature 让重试逐次提高采样温度，避免以相同温度重复得到同样的错误输出：
+// 第 1 次重试使用 start，之后每次增加 step，最高到 max。空回复重试、回显重试和严格指令重新提示都会使用，
+// 每次翻译调用重新从 start 开始；首次请求仍使用模型的默认温度。默认不调整温度
+func WithRetryTemperature(start, step, max float64) Option {
+	return func(o *options) {
+		o.retryTemperature = true
+		o.retryTemperatureStart = start
+		o.retryTemperatureStep = step
+		o.retryTemperatureMax = max
+	}
+}
+
+// retryOptions 返回第 retry 次重试（从 1 开始）的调用选项，未开启 WithRetryTemperature 时为空
+func (o *options) retryOptions(retry int) []llms.CallOption {
+	if !o.retryTemperature || retry < 1 {
+		return nil
+	}
+	temperature := min(o.retryTemperatureStart+o.retryTemperatureStep*float64(retry-1), o.retryTemperatureMax)
+	return []llms.CallOption{llms.WithTemperature(temperature)}
+}
+
+// guardEcho 在开启 WithEchoGuard 时处理回显的输出：重试直到不再回显，重试用完后返回 ErrEcho
+func guardEcho(ctx context.Context, llm llms.Model, text string, out string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	if !o.echoGuard {
+		return out, nil
+	}
+	for attempt := 0; isEcho(text, out, inputLanguage, outputLanguage); attempt++ {
+		if attempt >= o.echoRetries || !SpendRetry(ctx) {
+			return "", fmt.Errorf("translation failed after %d retries: %w", attempt, ErrEcho)
+		}
+		log.Printf("Translation output for '%s' echoes the input, retrying", text)
+		var err error
+		out, err = translateStrict(ctx, llm, text, inputLanguage, outputLanguage, o, o.retryOptions(attempt+1)...)
+		if err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+// translateStrict 使用更严格的指令重新翻译，用于纠正回显或附带解释的输出；extra 追加到模型调用选项
+func translateStrict(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options, extra ...llms.CallOption) (string, error) {
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"text":           text,
+	}
+	choices, err := runPromptChoices(ctx, llm, o, o.withHint(
+		`Translate the following {{.inputLanguage}} text into {{.outputLanguage}}.
+Respond with ONLY the {{.outputLanguage}} translation: no quotes, no labels, no explanations, and do not repeat the source text.
+
+Text: {{.text}}`, values), values, extra...)
+	if err != nil {
+		return "", fmt.Errorf("translation failed: %w", err)
+	}
+	out, err := o.parse(choices[0])
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("translation failed: %w", ErrEmptyResponse)
+	}
+	return out, nil
+}
@@ -0,0 +1,145 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestIsSuspiciousOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		out  string
+		in   string
+		lang string
+		want bool
+	}{
+		{name: "Clean", text: "Hello", out: "你好", in: "English", lang: "Chinese", want: false},
+		{name: "Echo", text: "Hello", out: "Hello", in: "English", lang: "Chinese", want: true},
+		{name: "Quoted Echo", text: "Hello", out: `"Hello"`, in: "English", lang: "Chinese", want: true},
+		{name: "Same Language Echo", text: "Hello", out: "Hello", in: "English", lang: "english", want: false},
+		{name: "Meta Phrase", text: "Hello", out: "Here is the translation: 你好", in: "English", lang: "Chinese", want: true},
+		{name: "Chinese Meta Phrase", text: "Hello", out: "翻译如下：你好", in: "English", lang: "Chinese", want: true},
+		{name: "Phrase In Source", text: "Translation: a guide", out: "Translation: 指南", in: "English", lang: "Chinese", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSuspiciousOutput(tt.text, tt.out, tt.in, tt.lang); got != tt.want {
+				t.Errorf("isSuspiciousOutput(%q, %q) = %v, want %v", tt.text, tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_RepromptsOnSuspiciousOutput(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Respond with ONLY") {
+			return "你好", nil
+		}
+		return "Here is the translation: 你好 (a common greeting)", nil
+	}}
+
+	result, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("expected 2 LLM calls, got %d", llm.Calls())
+	}
+	if cached, ok := defaultCache.Get("Hello", "English", "Chinese"); !ok || cached != "你好" {
+		t.Errorf("expected clean result cached, got %q, %v", cached, ok)
+	}
+}
+
+func TestTranslate_SuspiciousAfterReprompt(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "Hello", nil
+	}}
+
+	result, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "Hello" {
+		t.Errorf("Translate() = %q, want the last output", result)
+	}
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("suspicious output should not be cached")
+	}
+}
+
+func TestTranslate_EchoGuard(t *testing.T) {
+	defaultCache.Clear()
+	ctx := context.Background()
+	echo := &fakeLLM{respond: func(prompt string) (string, error) {
+		return " Good  morning ", nil
+	}}
+
+	_, err := Translate(ctx, echo, "Good morning", "English", "Chinese", WithEchoGuard(2))
+	if !errors.Is(err, ErrEcho) {
+		t.Fatalf("Translate() error = %v, want ErrEcho", err)
+	}
+	if echo.Calls() != 3 {
+		t.Errorf("LLM called %d times, want 3 (1 + 2 retries)", echo.Calls())
+	}
+	if _, ok := defaultCache.Get("Good morning", "English", "Chinese"); ok {
+		t.Error("echoed output should not be cached")
+	}
+
+	// 重试后给出真正的译文时正常返回并缓存
+	recovering := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Respond with ONLY") {
+			return "早上好", nil
+		}
+		return "Good morning", nil
+	}}
+	result, err := Translate(ctx, recovering, "Good morning", "English", "Chinese", WithEchoGuard(1))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "早上好" {
+		t.Errorf("Translate() = %q, want %q", result, "早上好")
+	}
+	if cached, _ := defaultCache.Get("Good morning", "English", "Chinese"); cached != "早上好" {
+		t.Errorf("cached = %q, want %q", cached, "早上好")
+	}
+}
+
+func TestWithRetryTemperature(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		// 始终回显原文，触发全部回显重试
+		if strings.Contains(prompt, "Good night") {
+			return "Good night", nil
+		}
+		return "Good morning", nil
+	}}
+	opts := []Option{WithEchoGuard(3), WithRetryTemperature(0.3, 0.2, 0.6)}
+
+	// 首次请求使用默认温度，之后逐次升温，超过上限后保持在上限
+	want := []float64{0, 0.3, 0.5, 0.6}
+	for _, text := range []string{"Good morning", "Good night"} {
+		start := len(llm.options)
+		if _, err := Translate(context.Background(), llm, text, "English", "Chinese", opts...); !errors.Is(err, ErrEcho) {
+			t.Fatalf("Translate(%q) error = %v, want ErrEcho", text, err)
+		}
+		calls := llm.options[start:]
+		if len(calls) != len(want) {
+			t.Fatalf("Translate(%q) made %d calls, want %d", text, len(calls), len(want))
+		}
+		for i, opts := range calls {
+			if math.Abs(opts.Temperature-want[i]) > 1e-9 {
+				t.Errorf("Translate(%q) call %d temperature = %v, want %v", text, i, opts.Temperature, want[i])
+			}
+		}
+	}
+}
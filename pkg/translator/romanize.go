@@ -0,0 +1,96 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// romanizePrompt 要求模型给出译文的拉丁字母转写
+const romanizePrompt = `Transliterate the following {{.language}} text into the Latin alphabet using the standard romanization system (for example Hanyu Pinyin with tone marks for Chinese, Hepburn romaji for Japanese). Reply with the romanization only.
+Text: {{.text}}`
+
+// WithRomanization 让 TranslateBatchResults 为每条译文额外请求罗马字转写（如中文拼音、日文罗马字），
+// 填入 BatchResult.Romanized，方便学习者朗读。转写不写入缓存，也不影响缓存的译文；
+// 目标语言本身使用拉丁字母时不做转写
+func WithRomanization() Option {
+	return func(o *options) {
+		o.romanization = true
+	}
+}
+
+// Romanize 返回 language 文本的拉丁字母转写
+func Romanize(ctx context.Context, llm llms.Model, text string, language string, opts ...Option) (string, error) {
+	if text == "" {
+		return "", ErrEmptyText
+	}
+	if language == "" {
+		return "", ErrEmptyOutputLanguage
+	}
+	return romanize(ctx, llm, text, language, newOptions(opts))
+}
+
+func romanize(ctx context.Context, llm llms.Model, text string, language string, o *options) (string, error) {
+	out, err := runPrompt(ctx, llm, o, romanizePrompt, map[string]any{
+		"language": language,
+		"text":     text,
+	})
+	if err != nil {
+		return "", fmt.Errorf("romanization failed: %w", err)
+	}
+	return o.parse(out)
+}
+
+// usesLatinScript 判断语言是否只使用拉丁字母，这类语言不需要转写
+func usesLatinScript(language string) bool {
+	scripts, ok := languageScripts[strings.ToLower(normalizeLanguage(language))]
+	return ok && len(scripts) == 1 && scripts[0] == unicode.Latin
+}
+
+// romanizeResults 为批量结果中的译文并发请求转写，回退为原文的条目不处理
+func romanizeResults(ctx context.Context, llm llms.Model, results []BatchResult, outputLanguage string, o *options) error {
+	if usesLatinScript(outputLanguage) {
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	// 限制并发数
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	for i := range results {
+		if results[i].Fallback || results[i].Text == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(result *BatchResult) {
+			defer wg.Done()
+
+			// 获取信号量
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			romanized, err := romanize(ctx, llm, result.Text, outputLanguage, o)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			result.Romanized = romanized
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return firstErr
+}
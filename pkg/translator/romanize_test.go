@@ -0,0 +1,62 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// newRomanizingLLM 返回翻译 Hello 为“你好”、并对转写请求回复拼音的假模型
+func newRomanizingLLM() *fakeLLM {
+	return &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Transliterate") {
+			return "nǐ hǎo", nil
+		}
+		return "你好", nil
+	}}
+}
+
+func TestTranslateBatchResults_Romanization(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	ctx := context.Background()
+
+	results, err := TranslateBatchResults(ctx, newRomanizingLLM(), []string{"Hello"}, "English", "Chinese", WithRomanization())
+	if err != nil {
+		t.Fatalf("TranslateBatchResults() error = %v", err)
+	}
+	if results[0].Text != "你好" || results[0].Romanized != "nǐ hǎo" {
+		t.Errorf("result = %+v, want 你好 / nǐ hǎo", results[0])
+	}
+
+	// 缓存中只有译文；缓存命中时也会填充转写
+	if cached, _ := defaultCache.Get("Hello", "English", "Chinese"); cached != "你好" {
+		t.Errorf("cached = %q, want 你好", cached)
+	}
+	results, err = TranslateBatchResults(ctx, newRomanizingLLM(), []string{"Hello"}, "English", "Chinese", WithRomanization())
+	if err != nil {
+		t.Fatalf("TranslateBatchResults() error = %v", err)
+	}
+	if results[0].Romanized != "nǐ hǎo" {
+		t.Errorf("Romanized on cache hit = %q, want nǐ hǎo", results[0].Romanized)
+	}
+}
+
+func TestTranslateBatchResults_RomanizationOff(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newRomanizingLLM()
+
+	results, err := TranslateBatchResults(context.Background(), llm, []string{"Hello"}, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatchResults() error = %v", err)
+	}
+	if results[0].Romanized != "" {
+		t.Errorf("Romanized = %q, want empty without WithRomanization", results[0].Romanized)
+	}
+	for _, prompt := range llm.prompts {
+		if strings.Contains(prompt, "Transliterate") {
+			t.Error("romanization requested without WithRomanization")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// minScriptRatio 是输入中属于源语言书写系统的字母所占的最低比例
+const minScriptRatio = 0.5
+
+// languageScripts 把语言名称（小写）映射为该语言使用的书写系统
+var languageScripts = map[string][]*unicode.RangeTable{
+	"english":    {unicode.Latin},
+	"french":     {unicode.Latin},
+	"german":     {unicode.Latin},
+	"spanish":    {unicode.Latin},
+	"italian":    {unicode.Latin},
+	"portuguese": {unicode.Latin},
+	"dutch":      {unicode.Latin},
+	"chinese":    {unicode.Han},
+	"japanese":   {unicode.Hiragana, unicode.Katakana, unicode.Han},
+	"korean":     {unicode.Hangul, unicode.Han},
+	"russian":    {unicode.Cyrillic},
+	"ukrainian":  {unicode.Cyrillic},
+	"arabic":     {unicode.Arabic},
+	"greek":      {unicode.Greek},
+	"hebrew":     {unicode.Hebrew},
+	"thai":       {unicode.Thai},
+}
+
+// WithScriptCheck 在调用模型前检查输入文本的 Unicode 书写系统是否与声明的源语言相符，
+// 例如声明为 English 的文本大部分是汉字时返回 ErrScriptMismatch，避免模型空转或输出乱码。
+// 无法识别的语言不做检查
+func WithScriptCheck() Option {
+	return func(o *options) {
+		o.scriptCheck = true
+	}
+}
+
+// checkScript 判断 text 中的字母是否主要属于 language 使用的书写系统。
+// 没有字母（如纯数字、标点）或语言未知时视为通过
+func checkScript(text, language string) error {
+	scripts, ok := languageScripts[strings.ToLower(normalizeLanguage(language))]
+	if !ok {
+		return nil
+	}
+
+	letters, matched := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsOneOf(scripts, r) {
+			matched++
+		}
+	}
+	if letters == 0 || float64(matched)/float64(letters) >= minScriptRatio {
+		return nil
+	}
+	return fmt.Errorf("%w: only %d of %d letters match %s", ErrScriptMismatch, matched, letters, language)
+}
@@ -0,0 +1,70 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		wantErr  bool
+	}{
+		{name: "Latin As English", text: "Hello, world!", language: "English"},
+		{name: "CJK As English", text: "你好，世界", language: "English", wantErr: true},
+		{name: "Mostly CJK As English", text: "你好世界 OK", language: "English", wantErr: true},
+		{name: "Mixed Mostly Latin", text: "Open the 设置 menu and click save", language: "English"},
+		{name: "CJK As Chinese", text: "你好，世界", language: "Chinese"},
+		{name: "Latin As Chinese", text: "Hello world", language: "Chinese", wantErr: true},
+		{name: "Kana As Japanese", text: "こんにちは世界", language: "Japanese"},
+		{name: "Cyrillic As Russian", text: "Привет, мир", language: "Russian"},
+		{name: "Language Code", text: "你好", language: "en-US", wantErr: true},
+		{name: "Case Insensitive", text: "Hello", language: "english"},
+		{name: "No Letters", text: "12345 !?", language: "English"},
+		{name: "Unknown Language", text: "你好", language: "Klingon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScript(tt.text, tt.language)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkScript(%q, %q) error = %v, wantErr %v", tt.text, tt.language, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrScriptMismatch) {
+				t.Errorf("checkScript() error = %v, want ErrScriptMismatch", err)
+			}
+		})
+	}
+}
+
+func TestTranslate_ScriptCheck(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好", "你好": "你好"})
+
+	// 声明为英文的中文文本：检查触发，不调用模型
+	_, err := Translate(ctx, llm, "你好", "English", "Chinese", WithScriptCheck())
+	if !errors.Is(err, ErrScriptMismatch) {
+		t.Errorf("Translate() error = %v, want ErrScriptMismatch", err)
+	}
+	if n := llm.Calls(); n != 0 {
+		t.Errorf("LLM called %d times, want 0", n)
+	}
+
+	// 拉丁字母的英文文本正常翻译
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithScriptCheck())
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+
+	// 未开启检查时不拦截
+	if _, err := Translate(ctx, llm, "你好", "English", "Chinese"); errors.Is(err, ErrScriptMismatch) {
+		t.Errorf("Translate() without WithScriptCheck returned %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Splitter 把长文本切分为若干片段，供长文本翻译逐段处理。
+// 实现应保证按顺序拼接所有片段后与原文一致，分隔用的空白留在片段中即可
+type Splitter interface {
+	Split(text string) []string
+}
+
+// SplitterFunc 把普通函数适配为 Splitter
+type SplitterFunc func(text string) []string
+
+// Split 调用 f(text)
+func (f SplitterFunc) Split(text string) []string {
+	return f(text)
+}
+
+var (
+	// sentenceEndPattern 匹配句末标点及其后的空白；中文标点后不要求空白
+	sentenceEndPattern = regexp.MustCompile(`[.!?]+["')\]]*\s+|[。！？]+[”」』）]*\s*`)
+	// paragraphBreakPattern 匹配段落之间的空行
+	paragraphBreakPattern = regexp.MustCompile(`\n[ \t]*\n\s*`)
+)
+
+// SentenceSplitter 按句末标点切分文本
+type SentenceSplitter struct{}
+
+// Split 在每个句末标点（及其后的空白）之后切分
+func (SentenceSplitter) Split(text string) []string {
+	return splitAfter(text, sentenceEndPattern)
+}
+
+// ParagraphSplitter 按空行切分文本
+type ParagraphSplitter struct{}
+
+// Split 在每组空行之后切分
+func (ParagraphSplitter) Split(text string) []string {
+	return splitAfter(text, paragraphBreakPattern)
+}
+
+// splitAfter 在 pattern 的每个匹配结尾处切分 text，分隔符保留在前一个片段中
+func splitAfter(text string, pattern *regexp.Regexp) []string {
+	var chunks []string
+	start := 0
+	for _, m := range pattern.FindAllStringIndex(text, -1) {
+		if m[1] > start && m[1] < len(text) {
+			chunks = append(chunks, text[start:m[1]])
+			start = m[1]
+		}
+	}
+	if start < len(text) {
+		chunks = append(chunks, text[start:])
+	}
+	return chunks
+}
+
+// WithSplitter 设置长文本翻译的分段策略，默认按段落分割
+func WithSplitter(s Splitter) Option {
+	return func(o *options) {
+		o.splitter = s
+	}
+}
+
+// TranslateLongText 用配置的 Splitter 把长文本分段后逐段翻译，再按原顺序拼接。
+// 每段首尾的空白（如段落间的空行）原样保留，只含空白的片段不会发给模型。
+// 某段翻译失败时按 WithFailureFallback 处理：默认只返回错误；FallbackKeepOriginal 保留该段原文并继续；
+// FallbackPartial 停止并返回失败前已翻译的部分和错误
+func TranslateLongText(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
+	if text == "" {
+		return "", ErrEmptyText
+	}
+
+	o := newOptions(opts)
+	splitter := o.splitter
+	if splitter == nil {
+		splitter = ParagraphSplitter{}
+	}
+
+	var b strings.Builder
+	for i, chunk := range splitter.Split(text) {
+		content := strings.TrimSpace(chunk)
+		if content == "" {
+			b.WriteString(chunk)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if alreadyInLanguage(ctx, llm, content, outputLanguage, o) {
+			b.WriteString(chunk)
+			continue
+		}
+
+		result, err := Translate(ctx, llm, content, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			err = fmt.Errorf("failed to translate chunk %d: %w", i+1, err)
+			switch o.failureFallback {
+			case FallbackKeepOriginal:
+				log.Printf("Keeping original text for chunk %d: %v", i+1, err)
+				b.WriteString(chunk)
+				continue
+			case FallbackPartial:
+				return b.String(), err
+			default:
+				return "", err
+			}
+		}
+
+		// 保留片段首尾的空白，保证段落和句子之间的间隔与原文一致
+		leading := chunk[:strings.Index(chunk, content)]
+		trailing := chunk[len(leading)+len(content):]
+		b.WriteString(leading + result + trailing)
+	}
+	return b.String(), nil
+}
+
+// TranslateChunks 翻译调用方已经分好的片段，并按原顺序用 joiner 连接译文。
+// 片段通过 TranslateBatch 并发翻译并使用缓存；空白片段不调用模型、原样保留，
+// 保证输出的片段数和连接方式与输入一致。需要自动分段时使用 TranslateLongText
+func TranslateChunks(ctx context.Context, llm llms.Model, chunks []string, inputLanguage string, outputLanguage string, joiner string, opts ...Option) (string, error) {
+	if len(chunks) == 0 {
+		return "", ErrEmptyText
+	}
+
+	var texts []string
+	var positions []int
+	for i, chunk := range chunks {
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		texts = append(texts, chunk)
+		positions = append(positions, i)
+	}
+
+	translated := make([]string, len(chunks))
+	copy(translated, chunks)
+	if len(texts) > 0 {
+		results, err := TranslateBatch(ctx, llm, texts, inputLanguage, outputLanguage, opts...)
+		if err != nil {
+			return "", err
+		}
+		for i, pos := range positions {
+			translated[pos] = results[i]
+		}
+	}
+	return strings.Join(translated, joiner), nil
+}
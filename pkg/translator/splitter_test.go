@@ -0,0 +1,196 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSentenceSplitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "English Sentences", input: "Hello world. How are you? Fine!", want: []string{"Hello world. ", "How are you? ", "Fine!"}},
+		{name: "Chinese Sentences", input: "你好。你好吗？很好！", want: []string{"你好。", "你好吗？", "很好！"}},
+		{name: "Closing Quote", input: `He said "hi." Then left.`, want: []string{`He said "hi." `, "Then left."}},
+		{name: "No Space After Dot", input: "Version 1.2 is out", want: []string{"Version 1.2 is out"}},
+		{name: "Single Sentence", input: "Hello", want: []string{"Hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SentenceSplitter{}.Split(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+			if joined := strings.Join(got, ""); joined != tt.input {
+				t.Errorf("joined chunks = %q, want original %q", joined, tt.input)
+			}
+		})
+	}
+}
+
+func TestParagraphSplitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "Two Paragraphs", input: "First line\nsecond line\n\nNext paragraph", want: []string{"First line\nsecond line\n\n", "Next paragraph"}},
+		{name: "Blank Line With Spaces", input: "A\n  \n\nB\n", want: []string{"A\n  \n\n", "B\n"}},
+		{name: "Trailing Blank Lines", input: "A\n\n", want: []string{"A\n\n"}},
+		{name: "Single Paragraph", input: "One\nparagraph", want: []string{"One\nparagraph"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParagraphSplitter{}.Split(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+			if joined := strings.Join(got, ""); joined != tt.input {
+				t.Errorf("joined chunks = %q, want original %q", joined, tt.input)
+			}
+		})
+	}
+}
+
+func TestTranslateLongText(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world.":         "你好，世界。",
+		"Thank you.":           "谢谢。",
+		"Goodbye.":             "再见。",
+		"Thank you.\nGoodbye.": "谢谢。\n再见。",
+	})
+	text := "Hello world.\n\nThank you.\nGoodbye.\n"
+
+	got, err := TranslateLongText(context.Background(), llm, text, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateLongText() error = %v", err)
+	}
+	if want := "你好，世界。\n\n谢谢。\n再见。\n"; got != want {
+		t.Errorf("TranslateLongText() = %q, want %q", got, want)
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("expected 2 calls with paragraph splitting, got %d", llm.Calls())
+	}
+
+	defaultCache.Clear()
+	llm = newDictLLM(map[string]string{
+		"Hello world.": "你好，世界。",
+		"Thank you.":   "谢谢。",
+		"Goodbye.":     "再见。",
+	})
+	got, err = TranslateLongText(context.Background(), llm, text, "English", "Chinese", WithSplitter(SentenceSplitter{}))
+	if err != nil {
+		t.Fatalf("TranslateLongText() error = %v", err)
+	}
+	if want := "你好，世界。\n\n谢谢。\n再见。\n"; got != want {
+		t.Errorf("TranslateLongText() with sentence splitter = %q, want %q", got, want)
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("expected 3 calls with sentence splitting, got %d", llm.Calls())
+	}
+}
+
+func TestTranslateLongText_FailedChunk(t *testing.T) {
+	llm := newDictLLM(map[string]string{
+		"First.":  "第一。",
+		"Second.": "第二。",
+		"Fourth.": "第四。",
+	})
+	// 第三段不在词典中，翻译失败
+	text := "First.\n\nSecond.\n\nThird.\n\nFourth."
+
+	tests := []struct {
+		name     string
+		fallback FailureFallback
+		want     string
+		wantErr  bool
+	}{
+		{"strict", FallbackStrict, "", true},
+		{"keep original", FallbackKeepOriginal, "第一。\n\n第二。\n\nThird.\n\n第四。", false},
+		{"partial", FallbackPartial, "第一。\n\n第二。\n\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			got, err := TranslateLongText(context.Background(), llm, text, "English", "Chinese", WithFailureFallback(tt.fallback))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TranslateLongText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "chunk 3") {
+				t.Errorf("error %q does not name the failed chunk", err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslateLongText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateLongText_CustomSplitter(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"alpha": "甲", "beta": "乙", "gamma": "丙"})
+
+	var called bool
+	lineSplitter := SplitterFunc(func(text string) []string {
+		called = true
+		return splitAfter(text, regexp.MustCompile(`\n`))
+	})
+
+	got, err := TranslateLongText(context.Background(), llm, "alpha\nbeta\ngamma", "English", "Chinese", WithSplitter(lineSplitter))
+	if err != nil {
+		t.Fatalf("TranslateLongText() error = %v", err)
+	}
+	if !called {
+		t.Error("expected custom splitter to be used")
+	}
+	if want := "甲\n乙\n丙"; got != want {
+		t.Errorf("TranslateLongText() = %q, want %q", got, want)
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("expected 3 LLM calls, got %d", llm.Calls())
+	}
+}
+
+func TestTranslateChunks(t *testing.T) {
+	withoutBatchDelay(t)
+	llm := newDictLLM(map[string]string{
+		"First part.":  "第一部分。",
+		"Second part.": "第二部分。",
+		"Third part.":  "第三部分。",
+	})
+	chunks := []string{"First part.", "Second part.", "  ", "Third part."}
+
+	tests := []struct {
+		name   string
+		joiner string
+		want   string
+	}{
+		{"newline", "\n", "第一部分。\n第二部分。\n  \n第三部分。"},
+		{"empty joiner", "", "第一部分。第二部分。  第三部分。"},
+		{"multi-char joiner", " | ", "第一部分。 | 第二部分。 |    | 第三部分。"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			got, err := TranslateChunks(context.Background(), llm, chunks, "English", "Chinese", tt.joiner)
+			if err != nil {
+				t.Fatalf("TranslateChunks() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslateChunks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := TranslateChunks(context.Background(), llm, nil, "English", "Chinese", "\n"); err != ErrEmptyText {
+		t.Errorf("TranslateChunks(nil) error = %v, want ErrEmptyText", err)
+	}
+}
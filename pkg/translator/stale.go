@@ -0,0 +1,81 @@
+package translator
+
+import (
+	"context"
+	"log"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// WithStaleWhileRevalidate 开启 stale-while-revalidate：过期条目不再被读取清理，
+// Get 照常返回它（可以用 Lookup 区分），Translate 和 TranslateBatch 等批量函数立即返回旧译文并在后台重新翻译、刷新缓存。
+// 适合对延迟敏感、可以接受短时间旧译文的场景。后台刷新与普通翻译一样受限流和全局并发限制，
+// 同一个键同时只有一次刷新；Close 会取消进行中的刷新并等待其退出。WithSweepInterval 的后台清理在此模式下不删除过期条目
+func WithStaleWhileRevalidate() CacheOption {
+	return func(c *TranslationCache) {
+		c.staleWhileRevalidate = true
+	}
+}
+
+// Lookup 与 Get 相同，但同时返回条目是否已经过期。只有开启 WithStaleWhileRevalidate 时才可能返回过期条目
+func (c *TranslationCache) Lookup(text, inputLang, outputLang string) (result string, stale bool, ok bool) {
+	return c.lookupKey(getCacheKey(text, inputLang, outputLang))
+}
+
+// startRefresh 登记对 key 的后台刷新并计入 Close 等待的后台任务。已有刷新在进行或缓存已关闭时返回 false。
+// 与 Close 关闭 stop 在同一把锁内，保证登记成功的刷新一定会被 Close 等待
+func (c *TranslationCache) startRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.stop:
+		return false
+	default:
+	}
+	if _, ok := c.refreshing[key]; ok {
+		return false
+	}
+	c.refreshing[key] = struct{}{}
+	c.wg.Add(1)
+	return true
+}
+
+// finishRefresh 取消对 key 的刷新登记
+func (c *TranslationCache) finishRefresh(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.refreshing, key)
+	c.wg.Done()
+}
+
+// revalidate 在缓存的后台任务中重新翻译过期的条目。刷新不受调用方 context 取消的影响，
+// 但保留其中的值（如模型覆盖和重试预算），并按配置的单次超时限制时长；失败时保留旧译文
+func (o *options) revalidate(ctx context.Context, llm llms.Model, text string, cacheText string, inputLanguage string, outputLanguage string, key string) {
+	c := o.translationCache()
+	if !c.startRefresh(key) {
+		return
+	}
+
+	go func() {
+		defer c.finishRefresh(key)
+
+		ctx, cancel := o.withDeadline(context.WithoutCancel(ctx))
+		defer cancel()
+		ctx, cancelOnStop := context.WithCancel(ctx)
+		defer cancelOnStop()
+		go func() {
+			select {
+			case <-c.stop:
+				cancelOnStop()
+			case <-ctx.Done():
+			}
+		}()
+
+		// 不经过 translateGroup：共享调用脱离调用方的 context，Close 无法取消它；同一个键的刷新已由 startRefresh 去重
+		if _, err := translateUncached(ctx, llm, text, cacheText, inputLanguage, outputLanguage, o); err != nil {
+			log.Printf("Background refresh failed for '%s', keeping the stale translation: %v", text, err)
+		}
+	}()
+}
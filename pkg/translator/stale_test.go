@@ -0,0 +1,174 @@
+package translator
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useCache 在测试期间用 c 替换共享缓存，结束时关闭 c 并恢复原来的缓存
+func useCache(t *testing.T, c *TranslationCache) {
+	t.Helper()
+	saved := defaultCache
+	defaultCache = c
+	t.Cleanup(func() {
+		c.Close()
+		defaultCache = saved
+	})
+}
+
+func TestTranslate_StaleWhileRevalidate(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now), WithStaleWhileRevalidate())
+	useCache(t, cache)
+
+	translations := []string{"你好", "您好"}
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return translations[0], nil
+	}}
+	ctx := context.Background()
+
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	clock.Advance(time.Hour)
+	translations = translations[1:]
+
+	// 过期条目仍可读取，并标记为过期
+	if got, stale, ok := cache.Lookup("Hello", "English", "Chinese"); !ok || !stale || got != "你好" {
+		t.Fatalf("Lookup() = %q, %v, %v, want the stale entry", got, stale, ok)
+	}
+	if got, ok := cache.Get("Hello", "English", "Chinese"); !ok || got != "你好" {
+		t.Errorf("Get() = %q, %v, want the stale entry", got, ok)
+	}
+
+	// 过期后立即返回旧译文，同时在后台刷新
+	got, err := Translate(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "你好" {
+		t.Errorf("Translate() after expiry = %q, want the stale %q", got, "你好")
+	}
+	cache.wg.Wait()
+
+	got, err = Translate(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "您好" {
+		t.Errorf("Translate() after refresh = %q, want %q", got, "您好")
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times, want 2 (initial translation and one refresh)", llm.Calls())
+	}
+	if _, stale, _ := cache.Lookup("Hello", "English", "Chinese"); stale {
+		t.Error("expected the refreshed entry to be fresh")
+	}
+}
+
+func TestTranslate_StaleWhileRevalidateClose(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now), WithStaleWhileRevalidate())
+	useCache(t, cache)
+	cache.Set("Hello", "English", "Chinese", "你好")
+	clock.Advance(time.Hour)
+
+	// 模型一直阻塞到 context 取消，Close 必须取消刷新并等待它退出
+	llm := &stallingLLM{}
+	if got, err := Translate(context.Background(), llm, "Hello", "English", "Chinese"); err != nil || got != "你好" {
+		t.Fatalf("Translate() = %q, %v, want the stale translation", got, err)
+	}
+	for llm.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cache.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not stop the background refresh")
+	}
+
+	// 刷新失败时保留旧译文；关闭后不再启动新的刷新
+	if got, err := Translate(context.Background(), llm, "Hello", "English", "Chinese"); err != nil || got != "你好" {
+		t.Errorf("Translate() after Close = %q, %v, want the stale translation", got, err)
+	}
+	if n := llm.calls.Load(); n != 1 {
+		t.Errorf("LLM called %d times, want 1", n)
+	}
+}
+
+func TestTranslateBatch_StaleWhileRevalidate(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now), WithStaleWhileRevalidate())
+	useCache(t, cache)
+	cache.SetMany(map[CacheKey]string{
+		{Text: "Hello", InputLang: "English", OutputLang: "Chinese"}: "你好",
+		{Text: "Bye", InputLang: "English", OutputLang: "Chinese"}:   "再见",
+	})
+	clock.Advance(time.Hour)
+
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Hello") {
+			return "您好", nil
+		}
+		return "再会", nil
+	}}
+	ctx := context.Background()
+
+	// 过期条目批量读取时同样照常返回
+	if got := cache.GetMany([]CacheKey{{Text: "Hello", InputLang: "English", OutputLang: "Chinese"}}); len(got) != 1 {
+		t.Errorf("GetMany() = %v, want the stale entry", got)
+	}
+
+	// 批量翻译立即返回旧译文，并在后台逐条刷新
+	got, err := TranslateBatch(ctx, llm, []string{"Hello", "Bye"}, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"你好", "再见"}; !slices.Equal(got, want) {
+		t.Errorf("TranslateBatch() after expiry = %v, want the stale %v", got, want)
+	}
+	cache.wg.Wait()
+
+	got, err = TranslateBatch(ctx, llm, []string{"Hello", "Bye"}, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"您好", "再会"}; !slices.Equal(got, want) {
+		t.Errorf("TranslateBatch() after refresh = %v, want %v", got, want)
+	}
+	if llm.Calls() != 2 {
+		t.Errorf("LLM called %d times, want 2 (one refresh per stale entry)", llm.Calls())
+	}
+}
+
+func TestStaleWhileRevalidate_SweepKeepsStaleEntries(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewTranslationCache(WithTTL(time.Hour), WithClock(clock.Now), WithStaleWhileRevalidate())
+	useCache(t, cache)
+
+	cache.Set("Hello", "English", "Chinese", "你好")
+	clock.Advance(2 * time.Hour)
+
+	// 清理任务先于读取方遇到过期条目时也不能删除它
+	if removed := cache.sweepExpired(); removed != 0 {
+		t.Errorf("sweepExpired() removed %d entries, want 0", removed)
+	}
+	if got, stale, ok := cache.Lookup("Hello", "English", "Chinese"); !ok || !stale || got != "你好" {
+		t.Errorf("Lookup() = %q, %v, %v, want the stale entry", got, stale, ok)
+	}
+
+	// Translate 仍然立即返回旧译文
+	llm := &fakeLLM{respond: func(string) (string, error) { return "您好", nil }}
+	if got, err := Translate(context.Background(), llm, "Hello", "English", "Chinese"); err != nil || got != "你好" {
+		t.Errorf("Translate() = %q, %v, want the stale %q", got, err, "你好")
+	}
+}
@@ -0,0 +1,55 @@
+package translator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TranslateStreamReader 逐行读取 r 中的文本，翻译后立即写入 w。
+// 整个文件不会被一次性读入内存；空行原样保留，末尾没有换行符的行也会被处理。
+func TranslateStreamReader(ctx context.Context, llm llms.Model, r io.Reader, w io.Writer, inputLanguage string, outputLanguage string) error {
+	reader := bufio.NewReader(r)
+	lineNo := 0
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return fmt.Errorf("failed to read line %d: %w", lineNo+1, readErr)
+		}
+		if line == "" && readErr != nil {
+			return nil
+		}
+		lineNo++
+
+		// 拆分行内容和行尾，保证输出的换行符与输入一致
+		content := strings.TrimRight(line, "\r\n")
+		ending := line[len(content):]
+
+		translated := content
+		if strings.TrimSpace(content) != "" {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			result, err := Translate(ctx, llm, content, inputLanguage, outputLanguage)
+			if err != nil {
+				return fmt.Errorf("failed to translate line %d: %w", lineNo, err)
+			}
+			translated = result
+		}
+
+		if _, err := io.WriteString(w, translated+ending); err != nil {
+			return fmt.Errorf("failed to write line %d: %w", lineNo, err)
+		}
+
+		if readErr != nil {
+			// 已处理最后一行（没有结尾换行符）
+			return nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateStreamReader(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello world":  "你好，世界",
+		"Good morning": "早上好",
+		"Thank you":    "谢谢",
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Multiple Lines",
+			input: "Hello world\nGood morning\n",
+			want:  "你好，世界\n早上好\n",
+		},
+		{
+			name:  "Blank Lines Preserved",
+			input: "Hello world\n\nThank you\n",
+			want:  "你好，世界\n\n谢谢\n",
+		},
+		{
+			name:  "Partial Final Line",
+			input: "Good morning\nThank you",
+			want:  "早上好\n谢谢",
+		},
+		{
+			name:  "CRLF Line Endings",
+			input: "Hello world\r\nThank you\r\n",
+			want:  "你好，世界\r\n谢谢\r\n",
+		},
+		{
+			name:  "Empty Input",
+			input: "",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := TranslateStreamReader(context.Background(), llm, strings.NewReader(tt.input), &out, "English", "Chinese")
+			if err != nil {
+				t.Fatalf("TranslateStreamReader() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("TranslateStreamReader() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateStreamReader_Error(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello world": "你好，世界"})
+
+	var out strings.Builder
+	err := TranslateStreamReader(context.Background(), llm, strings.NewReader("Hello world\nUnknown line\n"), &out, "English", "Chinese")
+	if err == nil {
+		t.Fatal("expected error for untranslatable line")
+	}
+	if !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error to mention line 2, got: %v", err)
+	}
+	// 出错前已翻译的行应当已经写出
+	if out.String() != "你好，世界\n" {
+		t.Errorf("partial output = %q, want %q", out.String(), "你好，世界\n")
+	}
+}
@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// explanationPhrases 是模型在译文后附加解释时常见的措辞（小写）
+var explanationPhrases = []string{
+	"this means",
+	"which means",
+	"meaning ",
+	"literally",
+	"in other words",
+	"意思是",
+	"意为",
+	"直译",
+	"也就是说",
+}
+
+// WithStrictOutput 对译文做第二次校验：单句原文却得到多句回复或带有解释性措辞时，
+// 优先提取回复中引号内的译文，没有引号时用更严格的指令重新翻译；仍不合格的结果不写入缓存
+func WithStrictOutput() Option {
+	return func(o *options) {
+		o.strictOutput = true
+	}
+}
+
+// hasExplanation 判断 out 是否在译文之外附带了解释：原文只有一句而回复有多句，
+// 或回复包含原文中没有的解释性措辞
+func hasExplanation(source, out string) bool {
+	if len((SentenceSplitter{}).Split(strings.TrimSpace(source))) <= 1 &&
+		len((SentenceSplitter{}).Split(strings.TrimSpace(out))) > 1 {
+		return true
+	}
+
+	lowerOut, lowerSource := strings.ToLower(out), strings.ToLower(source)
+	for _, phrase := range explanationPhrases {
+		if strings.Contains(lowerOut, phrase) && !strings.Contains(lowerSource, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractQuoted 返回 s 中第一段被成对引号包裹的非空文本
+func extractQuoted(s string) (string, bool) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		closing, ok := quotePairs[r]
+		if !ok {
+			i += size
+			continue
+		}
+		start := i + size
+		end := strings.IndexRune(s[start:], closing)
+		if end < 0 {
+			i = start
+			continue
+		}
+		if inner := strings.TrimSpace(s[start : start+end]); inner != "" {
+			return inner, true
+		}
+		// 跳过空的引号对，从结束引号之后继续查找
+		i = start + end + utf8.RuneLen(closing)
+	}
+	return "", false
+}
+
+// enforceOutputOnly 在启用 WithStrictOutput 时清理带解释的译文，返回清理后的译文以及是否合格
+func enforceOutputOnly(ctx context.Context, llm llms.Model, text string, out string, inputLanguage string, outputLanguage string, o *options) (string, bool, error) {
+	if !o.strictOutput || !hasExplanation(text, out) {
+		return out, true, nil
+	}
+
+	if quoted, ok := extractQuoted(out); ok && !hasExplanation(text, quoted) {
+		log.Printf("Extracted quoted translation from explained output: %s", out)
+		return quoted, true, nil
+	}
+
+	if !SpendRetry(ctx) {
+		return out, false, nil
+	}
+	log.Printf("Translation output contains an explanation: %s, reprompting", out)
+	out, err := translateStrict(ctx, llm, text, inputLanguage, outputLanguage, o, o.retryOptions(1)...)
+	if err != nil {
+		return "", false, err
+	}
+	if !hasExplanation(text, out) {
+		return out, true, nil
+	}
+	if quoted, ok := extractQuoted(out); ok && !hasExplanation(text, quoted) {
+		return quoted, true, nil
+	}
+	log.Printf("Translation output still contains an explanation after reprompt: %s", out)
+	return out, false, nil
+}
@@ -0,0 +1,119 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHasExplanation(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		out    string
+		want   bool
+	}{
+		{name: "Clean", source: "Hello", out: "你好", want: false},
+		{name: "Multi Sentence For Short Source", source: "Hello", out: "你好。这是一句问候语。", want: true},
+		{name: "Multi Sentence Source", source: "Hello. How are you?", out: "你好。你好吗？", want: false},
+		{name: "Explanation Phrase", source: "Hello", out: `"你好" — this means hello`, want: true},
+		{name: "Chinese Explanation", source: "Hello", out: "你好（意思是问候）", want: true},
+		{name: "Phrase In Source", source: "It literally rained cats", out: "It literally rained cats", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasExplanation(tt.source, tt.out); got != tt.want {
+				t.Errorf("hasExplanation(%q, %q) = %v, want %v", tt.source, tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractQuoted(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: `Translation: "你好" — this means hello`, want: "你好", wantOK: true},
+		{input: "译文是“你好”，意思是 hello", want: "你好", wantOK: true},
+		{input: `"" then "你好"`, want: "你好", wantOK: true},
+		{input: "你好，这是问候", wantOK: false},
+		{input: `"unterminated`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := extractQuoted(tt.input)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("extractQuoted(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestTranslate_StrictOutputExtractsQuoted(t *testing.T) {
+	ctx := context.Background()
+	llm := newDictLLM(map[string]string{"Hello": `Translation: "你好" — this means hello`})
+
+	defaultCache.Clear()
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithStrictOutput())
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+	if n := llm.Calls(); n != 1 {
+		t.Errorf("LLM called %d times, want 1 (extraction without reprompt)", n)
+	}
+	if cached, _ := defaultCache.Get("Hello", "English", "Chinese"); cached != "你好" {
+		t.Errorf("cached = %q, want extracted translation", cached)
+	}
+
+	// 未开启时保留模型输出
+	defaultCache.Clear()
+	result, err = Translate(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if !strings.Contains(result, "this means hello") {
+		t.Errorf("Translate() without strict output = %q, want the raw explanation kept", result)
+	}
+}
+
+func TestTranslate_StrictOutputReprompts(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		if strings.Contains(prompt, "Respond with ONLY") {
+			return "你好", nil
+		}
+		return "你好。这是一句常见的问候语。", nil
+	}}
+
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithStrictOutput())
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want reprompted %q", result, "你好")
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+}
+
+func TestTranslate_StrictOutputNotCachedWhenStillExplained(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		return "你好。这是一句常见的问候语。", nil
+	}}
+
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithStrictOutput()); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("explained output should not be cached")
+	}
+}
@@ -0,0 +1,121 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// structTagName 是标记待翻译字段的结构体标签，值为 "true" 的字段会被翻译
+const structTagName = "translate"
+
+// TranslateStruct 翻译 v 中带有 `translate:"true"` 标签的导出字段，并原地写回。
+// v 必须是指向结构体的非空指针。标签可以用在 string 和 []string 字段上；
+// 嵌套的结构体、结构体指针以及结构体的切片、数组和 map 值会递归处理，其中的字段同样按标签决定是否翻译。
+// 未标记的字段和空字符串保持不变；所有待翻译的文本去重后通过 TranslateBatch 一次性翻译
+func TranslateStruct(ctx context.Context, llm llms.Model, v any, inputLanguage string, outputLanguage string, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("TranslateStruct requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	c := &structCollector{visited: make(map[uintptr]bool), index: make(map[string]int)}
+	c.walk(rv.Elem())
+	if len(c.texts) == 0 {
+		return nil
+	}
+
+	results, err := TranslateBatch(ctx, llm, c.texts, inputLanguage, outputLanguage, opts...)
+	if err != nil {
+		return err
+	}
+	for i, fields := range c.fields {
+		for _, field := range fields {
+			field.SetString(results[i])
+		}
+	}
+	return nil
+}
+
+// structCollector 收集结构体中待翻译的字符串，相同的文本只翻译一次
+type structCollector struct {
+	texts   []string          // 去重后的待翻译文本
+	fields  [][]reflect.Value // fields[i] 是内容为 texts[i] 的所有可写字符串
+	index   map[string]int    // 文本在 texts 中的下标
+	visited map[uintptr]bool  // 已访问的指针，防止循环引用导致无限递归
+}
+
+// add 记录一个待翻译的字符串
+func (c *structCollector) add(s reflect.Value) {
+	text := s.String()
+	if text == "" || !s.CanSet() {
+		return
+	}
+	i, ok := c.index[text]
+	if !ok {
+		i = len(c.texts)
+		c.index[text] = i
+		c.texts = append(c.texts, text)
+		c.fields = append(c.fields, nil)
+	}
+	c.fields[i] = append(c.fields[i], s)
+}
+
+// walk 递归查找 v 中带标签的字段
+func (c *structCollector) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || c.visited[v.Pointer()] {
+			return
+		}
+		c.visited[v.Pointer()] = true
+		c.walk(v.Elem())
+	case reflect.Interface:
+		// 接口中的值不可寻址，无法原地写回
+		return
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get(structTagName) == "true" {
+				c.addTagged(v.Field(i))
+				continue
+			}
+			c.walk(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.walk(v.Index(i))
+		}
+	case reflect.Map:
+		// map 的值不可寻址，只处理指针值
+		iter := v.MapRange()
+		for iter.Next() {
+			if iter.Value().Kind() == reflect.Pointer {
+				c.walk(iter.Value())
+			}
+		}
+	}
+}
+
+// addTagged 收集带标签字段中的字符串：string、*string 以及字符串的切片和数组；
+// 其他类型的字段按未标记处理
+func (c *structCollector) addTagged(v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.String:
+		c.add(v)
+	case v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.String:
+		c.add(v.Elem())
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			c.add(v.Index(i))
+		}
+	default:
+		c.walk(v)
+	}
+}
@@ -0,0 +1,97 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type structTestAuthor struct {
+	Name string `translate:"false"`
+	Bio  string `translate:"true"`
+}
+
+type structTestSection struct {
+	Heading string `translate:"true"`
+	Anchor  string
+}
+
+type structTestArticle struct {
+	ID       string
+	Title    string   `translate:"true"`
+	Tags     []string `translate:"true"`
+	Slug     string
+	Author   *structTestAuthor
+	Sections []structTestSection
+	Note     *string `translate:"true"`
+	internal string  `translate:"true"`
+}
+
+func TestTranslateStruct(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		"Hello":        "你好",
+		"Greeting":     "问候",
+		"Introduction": "简介",
+		"A translator": "一名译者",
+		"Draft":        "草稿",
+		"Welcome":      "欢迎",
+	})
+
+	note := "Draft"
+	article := structTestArticle{
+		ID:       "Hello",
+		Title:    "Hello",
+		Tags:     []string{"Greeting", ""},
+		Slug:     "Hello",
+		Author:   &structTestAuthor{Name: "Hello", Bio: "A translator"},
+		Sections: []structTestSection{{Heading: "Introduction", Anchor: "Introduction"}, {Heading: "Welcome"}},
+		Note:     &note,
+		internal: "Hello",
+	}
+
+	if err := TranslateStruct(context.Background(), llm, &article, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateStruct() error = %v", err)
+	}
+
+	want := structTestArticle{
+		ID:       "Hello",
+		Title:    "你好",
+		Tags:     []string{"问候", ""},
+		Slug:     "Hello",
+		Author:   &structTestAuthor{Name: "Hello", Bio: "一名译者"},
+		Sections: []structTestSection{{Heading: "简介", Anchor: "Introduction"}, {Heading: "欢迎"}},
+		internal: "Hello",
+	}
+	draft := "草稿"
+	want.Note = &draft
+
+	if !reflect.DeepEqual(article, want) {
+		t.Errorf("TranslateStruct() result =\n%+v\nwant\n%+v", article, want)
+	}
+}
+
+func TestTranslateStruct_InvalidArgument(t *testing.T) {
+	llm := newDictLLM(nil)
+	ctx := context.Background()
+
+	var nilArticle *structTestArticle
+	for _, v := range []any{structTestArticle{}, nilArticle, new(string), nil} {
+		if err := TranslateStruct(ctx, llm, v, "English", "Chinese"); err == nil {
+			t.Errorf("TranslateStruct(%T) error = nil, want error", v)
+		}
+	}
+}
+
+func TestTranslateStruct_NothingToTranslate(t *testing.T) {
+	llm := newDictLLM(nil)
+	article := structTestArticle{ID: "Hello", Slug: "Hello"}
+
+	if err := TranslateStruct(context.Background(), llm, &article, "English", "Chinese"); err != nil {
+		t.Fatalf("TranslateStruct() error = %v", err)
+	}
+	if llm.Calls() != 0 {
+		t.Errorf("LLM called %d times, want 0", llm.Calls())
+	}
+}
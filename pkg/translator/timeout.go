@@ -0,0 +1,29 @@
+package translator
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout 设置单次翻译的总时间预算，覆盖其中所有的模型调用：空回复重试、重新提示、质量评估等。
+// 每次模型调用仍单独限时 defaultTimeout，但不会超过剩余的预算；预算用完后不再发起新的调用。
+// 调用方 context 自带的截止时间同样按总预算对待，两者取较早的一个
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// withDeadline 在设置了 WithTimeout 时为整次翻译派生带总超时的 context
+func (o *options) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+// attemptContext 为单次模型调用派生 context：超时为 defaultTimeout，
+// 父 context 剩余的时间更短时以父 context 的截止时间为准
+func attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, defaultTimeout)
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// stallingLLM 一直阻塞到 context 结束，然后返回空内容而不是错误，
+// 模拟吞掉取消错误的客户端：调用方若不检查剩余预算就会继续重试
+type stallingLLM struct {
+	calls atomic.Int32
+}
+
+func (s *stallingLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	s.calls.Add(1)
+	<-ctx.Done()
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: ""}}}, nil
+}
+
+func (s *stallingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, s, prompt, options...)
+}
+
+func TestTranslate_ParentDeadlineCoversRetries(t *testing.T) {
+	defaultCache.Clear()
+	llm := &stallingLLM{}
+	deadline := 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Translate(ctx, llm, "Hello deadline", "English", "Chinese")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 3*deadline {
+		t.Errorf("Translate() returned after %s, want close to the %s parent deadline", elapsed, deadline)
+	}
+	if got := llm.calls.Load(); got != 1 {
+		t.Errorf("LLM called %d times, want 1 (no retry after the deadline)", got)
+	}
+}
+
+func TestTranslate_WithTimeout(t *testing.T) {
+	defaultCache.Clear()
+	llm := &stallingLLM{}
+	budget := 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := Translate(context.Background(), llm, "Hello budget", "English", "Chinese", WithTimeout(budget))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*budget {
+		t.Errorf("Translate() returned after %s, want close to the %s budget", elapsed, budget)
+	}
+}
+
+func TestTranslateWithTool_WithTimeout(t *testing.T) {
+	defaultCache.Clear()
+	budget := 50 * time.Millisecond
+
+	start := time.Now()
+	if _, err := TranslateWithTool(context.Background(), &stallingLLM{}, "Hello tool budget", "English", "Chinese", WithTimeout(budget)); err == nil {
+		t.Error("TranslateWithTool() error = nil, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 3*budget {
+		t.Errorf("TranslateWithTool() returned after %s, want close to the %s budget", elapsed, budget)
+	}
+}
@@ -0,0 +1,207 @@
+package translator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// tmxDocument 是 TMX 1.4 文档的根元素
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+// tmxUnit 是一个翻译单元，包含同一段文本在各语言中的版本
+type tmxUnit struct {
+	SrcLang  string       `xml:"srclang,attr,omitempty"`
+	Variants []tmxVariant `xml:"tuv"`
+}
+
+type tmxVariant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Segment string `xml:"seg"`
+}
+
+// ExportTMX 把默认缓存中 srcLang -> tgtLang 的条目写为 TMX 格式
+func ExportTMX(w io.Writer, srcLang, tgtLang string) error {
+	return defaultCache.ExportTMX(w, srcLang, tgtLang)
+}
+
+// ExportTMX 把缓存中 srcLang -> tgtLang 的未过期条目写为 TMX 1.4 格式，供 CAT 工具导入。
+// 语言名称不区分大小写；TMX 要求语言代码，"English" 等名称写为 "en"，BCP-47 标签规范大小写后写入，
+// 无法转换为代码的语言返回错误。翻译单元按原文排序，保证输出稳定
+func (c *TranslationCache) ExportTMX(w io.Writer, srcLang, tgtLang string) error {
+	srcCode, err := tmxLanguageCode(srcLang)
+	if err != nil {
+		return err
+	}
+	tgtCode, err := tmxLanguageCode(tgtLang)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	now := c.clock()
+	var units []tmxUnit
+	for _, entry := range c.cache {
+		if entry.source == "" || now.Sub(entry.timestamp) >= c.ttl {
+			continue
+		}
+		if !strings.EqualFold(entry.inputLang, srcLang) || !strings.EqualFold(entry.outputLang, tgtLang) {
+			continue
+		}
+		units = append(units, tmxUnit{Variants: []tmxVariant{
+			{Lang: srcCode, Segment: entry.source},
+			{Lang: tgtCode, Segment: entry.result},
+		}})
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Variants[0].Segment < units[j].Variants[0].Segment
+	})
+
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "langchaingo-demo",
+			CreationToolVersion: "1.0",
+			SegType:             "sentence",
+			OTMF:                "langchaingo-demo",
+			AdminLang:           "en",
+			SrcLang:             srcCode,
+			DataType:            "plaintext",
+		},
+		Units: units,
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write TMX header: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode TMX: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write TMX: %w", err)
+	}
+	return nil
+}
+
+// tmxLanguageNames 把常见的语言代码映射为翻译接口使用的语言名称
+var tmxLanguageNames = map[string]string{
+	"en": "English",
+	"zh": "Chinese",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"fr": "French",
+	"de": "German",
+	"es": "Spanish",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"ar": "Arabic",
+}
+
+// tmxLanguageCode 把语言参数转换为 TMX 使用的语言代码：BCP-47 标签规范大小写，
+// tmxLanguageNames 中的语言名称转换为对应代码
+func tmxLanguageCode(lang string) (string, error) {
+	if isLocaleTag(lang) {
+		return canonicalLocale(lang), nil
+	}
+	for code, name := range tmxLanguageNames {
+		if strings.EqualFold(name, strings.TrimSpace(lang)) {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("no TMX language code for %q: use a BCP-47 tag such as \"en\" or \"zh-CN\"", lang)
+}
+
+// ImportTMX 把 TMX 文件中的翻译单元导入默认缓存
+func ImportTMX(r io.Reader) (int, error) {
+	return defaultCache.ImportTMX(r)
+}
+
+// ImportTMX 解析 TMX 文件，把每个翻译单元中源语言到其他各语言的译文写入缓存，返回写入的条目数。
+// 只有语言的代码（如 "en"、"zh"）转换为 "English"、"Chinese" 等名称；带地区或文字的标签（如 "zh-CN"、"zh-TW"）
+// 按 canonicalLocale 保留，与 Translate 使用同样的标签时命中，不同地区的译文互不覆盖。
+// 原文与 Translate 一样按 NFC 规范化后写入；重复的单元按文件中的顺序覆盖，后出现的生效
+func (c *TranslationCache) ImportTMX(r io.Reader) (int, error) {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+
+	imported := 0
+	for i, tu := range doc.Units {
+		srcLang := tu.SrcLang
+		if srcLang == "" {
+			srcLang = doc.Header.SrcLang
+		}
+		src := sourceVariant(tu.Variants, srcLang)
+		if src == nil || src.Segment == "" {
+			return imported, fmt.Errorf("translation unit %d has no source segment", i+1)
+		}
+
+		for _, tuv := range tu.Variants {
+			if strings.EqualFold(tuv.Lang, src.Lang) || tuv.Segment == "" {
+				continue
+			}
+			c.Set(norm.NFC.String(src.Segment), tmxImportLanguage(src.Lang), tmxImportLanguage(tuv.Lang), tuv.Segment)
+			imported++
+		}
+	}
+	return imported, nil
+}
+
+// tmxImportLanguage 把 TMX 中的语言代码转换为缓存使用的语言参数：带地区或文字的标签规范大小写后保留，
+// 只有语言的代码转换为名称
+func tmxImportLanguage(lang string) string {
+	if isLocaleTag(lang) && strings.ContainsAny(strings.TrimSpace(lang), "-_") {
+		return canonicalLocale(lang)
+	}
+	return normalizeLanguage(lang)
+}
+
+// sourceVariant 返回翻译单元中源语言的版本；srcLang 为空或为 "*all*" 时取第一个
+func sourceVariant(variants []tmxVariant, srcLang string) *tmxVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+	if srcLang != "" && srcLang != "*all*" {
+		for i := range variants {
+			if strings.EqualFold(variants[i].Lang, srcLang) {
+				return &variants[i]
+			}
+		}
+	}
+	return &variants[0]
+}
+
+// normalizeLanguage 把语言代码转换为语言名称，无法识别的代码原样返回
+func normalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(lang)
+	primary, _, _ := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	if name, ok := tmxLanguageNames[strings.ToLower(primary)]; ok {
+		return name
+	}
+	return lang
+}
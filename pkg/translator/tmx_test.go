@@ -0,0 +1,184 @@
+package translator
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestExportTMX(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Set("Thank you", "English", "Chinese", "谢谢")
+	defaultCache.Set("Hello <world> & co", "English", "Chinese", "你好")
+	defaultCache.Set("Hello", "English", "Japanese", "こんにちは")
+	defaultCache.setKey(hashKeyParts(detectCacheTag, "Hello"), "English")
+
+	var out strings.Builder
+	if err := ExportTMX(&out, "English", "Chinese"); err != nil {
+		t.Fatalf("ExportTMX() error = %v", err)
+	}
+
+	var doc tmxDocument
+	if err := xml.Unmarshal([]byte(out.String()), &doc); err != nil {
+		t.Fatalf("emitted TMX does not parse: %v\n%s", err, out.String())
+	}
+	if doc.Version != "1.4" || doc.Header.SrcLang != "en" {
+		t.Errorf("unexpected TMX header: version=%q srclang=%q", doc.Version, doc.Header.SrcLang)
+	}
+
+	want := [][2]string{{"Hello <world> & co", "你好"}, {"Thank you", "谢谢"}}
+	if len(doc.Units) != len(want) {
+		t.Fatalf("expected %d <tu> units, got %d:\n%s", len(want), len(doc.Units), out.String())
+	}
+	for i, tu := range doc.Units {
+		if len(tu.Variants) != 2 {
+			t.Fatalf("unit %d has %d <tuv>, want 2", i, len(tu.Variants))
+		}
+		src, tgt := tu.Variants[0], tu.Variants[1]
+		if src.Lang != "en" || tgt.Lang != "zh" {
+			t.Errorf("unit %d languages = %q/%q, want en/zh", i, src.Lang, tgt.Lang)
+		}
+		if src.Segment != want[i][0] || tgt.Segment != want[i][1] {
+			t.Errorf("unit %d = %q -> %q, want %q -> %q", i, src.Segment, tgt.Segment, want[i][0], want[i][1])
+		}
+	}
+	if !strings.Contains(out.String(), `xml:lang="en"`) {
+		t.Errorf("expected xml:lang attributes in output:\n%s", out.String())
+	}
+}
+
+func TestExportTMX_LanguageCodes(t *testing.T) {
+	defaultCache.Clear()
+	defaultCache.Set("Color", "en-US", "zh-TW", "顏色")
+
+	// 标签按 BCP-47 规范大小写写入
+	var out strings.Builder
+	if err := ExportTMX(&out, "en-us", "zh-tw"); err != nil {
+		t.Fatalf("ExportTMX() error = %v", err)
+	}
+	var doc tmxDocument
+	if err := xml.Unmarshal([]byte(out.String()), &doc); err != nil {
+		t.Fatalf("emitted TMX does not parse: %v", err)
+	}
+	if doc.Header.SrcLang != "en-US" || len(doc.Units) != 1 {
+		t.Fatalf("srclang = %q with %d units, want en-US with 1 unit:\n%s", doc.Header.SrcLang, len(doc.Units), out.String())
+	}
+	if got := doc.Units[0].Variants[1].Lang; got != "zh-TW" {
+		t.Errorf("target xml:lang = %q, want zh-TW", got)
+	}
+
+	// 无法转换为代码的语言名称返回错误，而不是写出无效的文件
+	if err := ExportTMX(&out, "Klingon", "Chinese"); err == nil {
+		t.Error("expected error for a language without a TMX code")
+	}
+}
+
+func TestImportTMX(t *testing.T) {
+	defaultCache.Clear()
+	fixture := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="test" creationtoolversion="1" segtype="sentence" o-tmf="test" adminlang="en" srclang="en" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en"><seg>Hello</seg></tuv>
+      <tuv xml:lang="zh"><seg>您好</seg></tuv>
+      <tuv xml:lang="ja"><seg>こんにちは</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="zh"><seg>谢谢</seg></tuv>
+      <tuv xml:lang="en"><seg>Thank you</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en"><seg>Hello</seg></tuv>
+      <tuv xml:lang="zh"><seg>你好</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en"><seg>Software</seg></tuv>
+      <tuv xml:lang="zh-CN"><seg>软件</seg></tuv>
+      <tuv xml:lang="zh_tw"><seg>軟體</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+	n, err := ImportTMX(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("ImportTMX() error = %v", err)
+	}
+	if n != 6 {
+		t.Errorf("ImportTMX() imported %d entries, want 6", n)
+	}
+
+	tests := []struct {
+		text, in, out, want string
+	}{
+		{"Hello", "English", "Chinese", "你好"}, // 重复的单元以后出现的为准
+		{"Hello", "English", "Japanese", "こんにちは"},
+		{"Thank you", "English", "Chinese", "谢谢"},
+		{"Software", "English", "zh-CN", "软件"}, // 不同地区的译文分别保存
+		{"Software", "English", "zh-TW", "軟體"},
+	}
+	for _, tt := range tests {
+		if got, ok := defaultCache.Get(tt.text, tt.in, tt.out); !ok || got != tt.want {
+			t.Errorf("cache for %q %s->%s = %q, %v, want hit %q", tt.text, tt.in, tt.out, got, ok, tt.want)
+		}
+	}
+
+	// 导入的条目直接命中缓存，不调用模型
+	llm := newDictLLM(nil)
+	if got, err := Translate(context.Background(), llm, "Thank you", "English", "Chinese"); err != nil || got != "谢谢" {
+		t.Errorf("Translate() = %q, %v, want cached %q", got, err, "谢谢")
+	}
+	if got, err := Translate(context.Background(), llm, "Software", "English", "zh-TW"); err != nil || got != "軟體" {
+		t.Errorf("Translate() = %q, %v, want cached %q", got, err, "軟體")
+	}
+	if llm.Calls() != 0 {
+		t.Errorf("expected no LLM calls for imported strings, got %d", llm.Calls())
+	}
+}
+
+func TestImportTMX_NormalizesUnicode(t *testing.T) {
+	defaultCache.Clear()
+	nfd := "Cafe\u0301"
+	fixture := `<tmx version="1.4"><header srclang="fr"/><body><tu>` +
+		`<tuv xml:lang="fr"><seg>` + nfd + `</seg></tuv><tuv xml:lang="zh"><seg>咖啡馆</seg></tuv>` +
+		`</tu></body></tmx>`
+	if _, err := ImportTMX(strings.NewReader(fixture)); err != nil {
+		t.Fatalf("ImportTMX() error = %v", err)
+	}
+
+	// 文件中是 NFD 写法，Translate 按 NFC 计算缓存键，两种写法都应命中
+	llm := newDictLLM(nil)
+	for _, text := range []string{nfd, "Caf\u00e9"} {
+		if got, err := Translate(context.Background(), llm, text, "French", "Chinese"); err != nil || got != "咖啡馆" {
+			t.Errorf("Translate(%q) = %q, %v, want cached %q", text, got, err, "咖啡馆")
+		}
+	}
+	if llm.Calls() != 0 {
+		t.Errorf("expected no LLM calls for imported strings, got %d", llm.Calls())
+	}
+}
+
+func TestImportTMX_Errors(t *testing.T) {
+	if _, err := ImportTMX(strings.NewReader("<tmx><body>")); err == nil {
+		t.Error("expected error for malformed TMX")
+	}
+	if _, err := ImportTMX(strings.NewReader(`<tmx version="1.4"><header/><body><tu></tu></body></tmx>`)); err == nil {
+		t.Error("expected error for unit without segments")
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for input, want := range map[string]string{
+		"en-US":   "English",
+		"zh_CN":   "Chinese",
+		"JA":      "Japanese",
+		"English": "English",
+		"tlh":     "tlh",
+		"":        "",
+	} {
+		if got := normalizeLanguage(input); got != want {
+			t.Errorf("normalizeLanguage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -5,21 +5,59 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 
 	"github.com/tmc/langchaingo/callbacks"
-	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/tools"
 )
 
+// 工具输入未指定语言时使用的默认值
+const (
+	defaultSourceLanguage = "English"
+	defaultTargetLanguage = "Chinese"
+)
+
 // Translator 实现了 tools.Tool 接口用于翻译任务
 type Translator struct {
-	LLM              *openai.LLM
+	LLM              llms.Model
 	CallbacksHandler callbacks.Handler
+	Options          []Option // 每次翻译附加的选项，如 WithNoCache()
+}
+
+// fenceLanguageHints 是单行代码块中会被当作语言标记去掉的常见写法
+var fenceLanguageHints = map[string]bool{"json": true, "text": true, "txt": true, "plaintext": true, "markdown": true, "md": true}
+
+// fenceInfoPattern 匹配多行代码块第一行的语言标记，可以为空
+var fenceInfoPattern = regexp.MustCompile(`^[\w+-]*$`)
+
+// stripInputFence 去掉包裹整个工具输入的 Markdown 代码块及其语言标记，例如 "```json {...} ```"；
+// 不是代码块时原样返回
+func stripInputFence(input string) string {
+	s := strings.TrimSpace(input)
+	if len(s) < 6 || !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") {
+		return input
+	}
+	inner := s[3 : len(s)-3]
+
+	// 多行代码块：第一行是语言标记
+	if first, rest, ok := strings.Cut(inner, "\n"); ok && fenceInfoPattern.MatchString(strings.TrimSpace(first)) {
+		return strings.TrimSpace(rest)
+	}
+	// 单行代码块：只去掉已知的语言标记，避免把正文的第一个词当作标记
+	inner = strings.TrimSpace(inner)
+	if hint, rest, ok := strings.Cut(inner, " "); ok && fenceLanguageHints[strings.ToLower(hint)] {
+		return strings.TrimSpace(rest)
+	}
+	if lower := strings.ToLower(inner); strings.HasPrefix(lower, "json{") {
+		return inner[len("json"):]
+	}
+	return inner
 }
 
 // NewTranslator 创建一个新的翻译器实例
-func NewTranslator(llm *openai.LLM) *Translator {
+func NewTranslator(llm llms.Model) *Translator {
 	return &Translator{
 		LLM: llm,
 	}
@@ -33,6 +71,9 @@ func (t *Translator) Call(ctx context.Context, input string) (string, error) {
 		t.CallbacksHandler.HandleToolStart(ctx, input)
 	}
 
+	// agent 有时把参数包在 Markdown 代码块里，先去掉代码块再判断格式
+	input = stripInputFence(input)
+
 	// 尝试解析 JSON 输入
 	var text, sourceLang, targetLang string
 	if strings.HasPrefix(strings.TrimSpace(input), "{") {
@@ -53,24 +94,31 @@ func (t *Translator) Call(ctx context.Context, input string) (string, error) {
 	if text == "" {
 		text = strings.Trim(input, "'\"")
 		text = strings.TrimSpace(text)
-		sourceLang = "English"
-		targetLang = "Chinese"
+		sourceLang = defaultSourceLanguage
+		targetLang = defaultTargetLanguage
 	}
 
 	// 设置默认值
 	if sourceLang == "" {
-		sourceLang = "English"
+		sourceLang = defaultSourceLanguage
 	}
 	if targetLang == "" {
-		targetLang = "Chinese"
+		targetLang = defaultTargetLanguage
 	}
 
 	log.Printf("Translating '%s' from %s to %s", text, sourceLang, targetLang)
 
-	// 使用内置的 translate 函数进行实际翻译
-	result, err := Translate(ctx, t.LLM, text, sourceLang, targetLang)
+	// 使用内置的 translate 函数进行实际翻译，回调处理器同时用于观察 LLM 调用
+	opts := append([]Option(nil), t.Options...)
+	if t.CallbacksHandler != nil {
+		opts = append(opts, WithCallbacks(t.CallbacksHandler))
+	}
+	result, err := Translate(ctx, t.LLM, text, sourceLang, targetLang, opts...)
 	if err != nil {
 		log.Printf("Translation error: %v", err)
+		if t.CallbacksHandler != nil {
+			t.CallbacksHandler.HandleToolError(ctx, err)
+		}
 		return "", fmt.Errorf("translation failed: %w", err)
 	}
 
@@ -97,5 +145,39 @@ func (t *Translator) Name() string {
 	return "translate_text"
 }
 
+// Parameters 返回工具输入的 JSON Schema，供支持 function calling 的 agent 传递结构化参数。
+// text 为必填项，两个语言参数可选并带有默认值
+func (t *Translator) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"text": map[string]any{
+				"type":        "string",
+				"description": "The text to translate",
+			},
+			"source_language": map[string]any{
+				"type":        "string",
+				"description": "The language of the text",
+				"default":     defaultSourceLanguage,
+			},
+			"target_language": map[string]any{
+				"type":        "string",
+				"description": "The language to translate into",
+				"default":     defaultTargetLanguage,
+			},
+		},
+		"required": []string{"text"},
+	}
+}
+
+// FunctionDefinition 把工具描述为 llms.FunctionDefinition，可通过 llms.WithTools 提供给模型
+func (t *Translator) FunctionDefinition() llms.FunctionDefinition {
+	return llms.FunctionDefinition{
+		Name:        t.Name(),
+		Description: "Translate text between languages.",
+		Parameters:  t.Parameters(),
+	}
+}
+
 // 确保 Translator 实现了 tools.Tool 接口
 var _ tools.Tool = (*Translator)(nil)
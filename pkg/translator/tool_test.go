@@ -2,6 +2,8 @@ package translator
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/tmc/langchaingo/llms"
@@ -10,30 +12,46 @@ import (
 
 // mockCallbackHandler 用于测试的回调处理器
 type mockCallbackHandler struct {
-	startCalled bool
-	endCalled   bool
+	startCalled      bool
+	endCalled        bool
+	llmStartCalled   bool
+	llmEndCalled     bool
+	llmErrorCalled   bool
+	chainStartCalled bool
+	chainEndCalled   bool
+	toolErrorCalled  bool
 }
 
-func (m *mockCallbackHandler) HandleText(ctx context.Context, text string)                 {}
-func (m *mockCallbackHandler) HandleLLMStart(ctx context.Context, prompts []string)        {}
-func (m *mockCallbackHandler) HandleLLMEnd(ctx context.Context, output string)             {}
-func (m *mockCallbackHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {}
-func (m *mockCallbackHandler) HandleChainEnd(ctx context.Context, outputs map[string]any)  {}
+func (m *mockCallbackHandler) HandleText(ctx context.Context, text string)          {}
+func (m *mockCallbackHandler) HandleLLMStart(ctx context.Context, prompts []string) {}
+func (m *mockCallbackHandler) HandleLLMEnd(ctx context.Context, output string)      {}
+func (m *mockCallbackHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	m.chainStartCalled = true
+}
+func (m *mockCallbackHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	m.chainEndCalled = true
+}
 func (m *mockCallbackHandler) HandleToolStart(ctx context.Context, input string) {
 	m.startCalled = true
 }
 func (m *mockCallbackHandler) HandleToolEnd(ctx context.Context, output string) {
 	m.endCalled = true
 }
-func (m *mockCallbackHandler) HandleToolError(ctx context.Context, err error)                   {}
+func (m *mockCallbackHandler) HandleToolError(ctx context.Context, err error) {
+	m.toolErrorCalled = true
+}
 func (m *mockCallbackHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {}
 func (m *mockCallbackHandler) HandleAgentEnd(ctx context.Context, action schema.AgentFinish)    {}
 func (m *mockCallbackHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {}
 func (m *mockCallbackHandler) HandleChainError(ctx context.Context, err error)                  {}
-func (m *mockCallbackHandler) HandleLLMError(ctx context.Context, err error)                    {}
+func (m *mockCallbackHandler) HandleLLMError(ctx context.Context, err error) {
+	m.llmErrorCalled = true
+}
 func (m *mockCallbackHandler) HandleLLMGenerateContentStart(ctx context.Context, ms []llms.MessageContent) {
+	m.llmStartCalled = true
 }
 func (m *mockCallbackHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	m.llmEndCalled = true
 }
 func (m *mockCallbackHandler) HandleRetrieverStart(ctx context.Context, query string) {}
 func (m *mockCallbackHandler) HandleRetrieverEnd(ctx context.Context, query string, documents []schema.Document) {
@@ -69,3 +87,146 @@ func TestNewTranslator(t *testing.T) {
 		t.Error("NewTranslator() did not set LLM correctly")
 	}
 }
+
+func TestTranslator_CallWithCallbacks(t *testing.T) {
+	defaultCache.Clear()
+	handler := &mockCallbackHandler{}
+	translator := NewTranslator(newDictLLM(map[string]string{"Hello world": "你好，世界"}))
+	translator.CallbacksHandler = handler
+
+	result, err := translator.Call(context.Background(), `{"text": "Hello world", "source_language": "English", "target_language": "Chinese"}`)
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result != "你好，世界" {
+		t.Errorf("Call() = %q, want %q", result, "你好，世界")
+	}
+
+	if !handler.startCalled || !handler.endCalled {
+		t.Errorf("expected tool start/end hooks, got start=%v end=%v", handler.startCalled, handler.endCalled)
+	}
+	if !handler.llmStartCalled || !handler.llmEndCalled {
+		t.Errorf("expected LLM start/end hooks, got start=%v end=%v", handler.llmStartCalled, handler.llmEndCalled)
+	}
+}
+
+func TestStripInputFence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"single-line json", "```json {\"text\": \"Hello\"} ```", `{"text": "Hello"}`},
+		{"single-line json without space", "```json{\"text\": \"Hello\"}```", `{"text": "Hello"}`},
+		{"multi-line json", "```json\n{\"text\": \"Hello\"}\n```", `{"text": "Hello"}`},
+		{"multi-line without hint", "```\nHello world\n```", "Hello world"},
+		{"single-line plain text", "```Hello world```", "Hello world"},
+		{"single-line text hint", "```text Good morning```", "Good morning"},
+		{"surrounding whitespace", "  ```\nHello\n```  \n", "Hello"},
+		{"no fence", "Hello world", "Hello world"},
+		{"inline backticks", "Use `go test` here", "Use `go test` here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripInputFence(tt.input); got != tt.want {
+				t.Errorf("stripInputFence(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslator_CallFencedInput(t *testing.T) {
+	llm := newDictLLM(map[string]string{"Hello world": "Bonjour le monde", "Good morning": "早上好"})
+	translator := NewTranslator(llm)
+
+	defaultCache.Clear()
+	result, err := translator.Call(context.Background(), "```json\n{\"text\": \"Hello world\", \"source_language\": \"English\", \"target_language\": \"French\"}\n```")
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result != "Bonjour le monde" {
+		t.Errorf("Call() = %q, want %q", result, "Bonjour le monde")
+	}
+	if prompt := llm.prompts[len(llm.prompts)-1]; !strings.Contains(prompt, "French") || strings.Contains(prompt, "```") {
+		t.Errorf("fenced JSON was not parsed: %s", prompt)
+	}
+
+	// 代码块中的纯文本按默认语言翻译
+	result, err = translator.Call(context.Background(), "```text Good morning```")
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result != "早上好" {
+		t.Errorf("Call() = %q, want %q", result, "早上好")
+	}
+}
+
+func TestTranslator_CallToolError(t *testing.T) {
+	defaultCache.Clear()
+	handler := &mockCallbackHandler{}
+	translator := NewTranslator(newDictLLM(nil))
+	translator.CallbacksHandler = handler
+
+	if _, err := translator.Call(context.Background(), "Hello world"); err == nil {
+		t.Fatal("expected error from failing LLM")
+	}
+	if !handler.toolErrorCalled || !handler.llmErrorCalled {
+		t.Errorf("expected tool and LLM error hooks, got tool=%v llm=%v", handler.toolErrorCalled, handler.llmErrorCalled)
+	}
+}
+
+func TestTranslator_Parameters(t *testing.T) {
+	translator := NewTranslator(nil)
+
+	// 经过 JSON 序列化后按 schema 结构解析，模拟提供方收到的内容
+	raw, err := json.Marshal(translator.FunctionDefinition())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var def struct {
+		Name       string `json:"name"`
+		Parameters struct {
+			Type       string `json:"type"`
+			Properties map[string]struct {
+				Type    string `json:"type"`
+				Default string `json:"default"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(raw, &def); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if def.Name != translator.Name() {
+		t.Errorf("name = %q, want %q", def.Name, translator.Name())
+	}
+	if def.Parameters.Type != "object" {
+		t.Errorf("type = %q, want object", def.Parameters.Type)
+	}
+	if len(def.Parameters.Required) != 1 || def.Parameters.Required[0] != "text" {
+		t.Errorf("required = %v, want [text]", def.Parameters.Required)
+	}
+
+	wantDefaults := map[string]string{
+		"text":            "",
+		"source_language": "English",
+		"target_language": "Chinese",
+	}
+	if len(def.Parameters.Properties) != len(wantDefaults) {
+		t.Errorf("properties = %v, want %d entries", def.Parameters.Properties, len(wantDefaults))
+	}
+	for name, wantDefault := range wantDefaults {
+		prop, ok := def.Parameters.Properties[name]
+		if !ok {
+			t.Errorf("missing property %q", name)
+			continue
+		}
+		if prop.Type != "string" {
+			t.Errorf("%s type = %q, want string", name, prop.Type)
+		}
+		if prop.Default != wantDefault {
+			t.Errorf("%s default = %q, want %q", name, prop.Default, wantDefault)
+		}
+	}
+}
@@ -2,14 +2,17 @@ package translator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
-	"github.com/tmc/langchaingo/chains"
-	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
+	"golang.org/x/sync/singleflight"
 )
 
 // 配置常量
@@ -18,209 +21,535 @@ const (
 	cacheDuration  = 24 * time.Hour   // 缓存有效期
 	maxConcurrency = 2                // 最大并发数
 	batchSize      = 3                // 批处理大小
+
+	defaultBatchBudget = 2000 // 自动分组批量翻译时每次调用的默认 token 预算
+
+	emptyResponseRetries = 1 // 模型返回空内容时的重试次数
 )
 
-// TranslationCache 用于缓存翻译结果
-type TranslationCache struct {
-	cache map[string]cacheEntry
-	mu    sync.RWMutex
-}
+// 批量翻译的限流延迟，测试中可调小以加快执行
+var (
+	itemDelay  = 500 * time.Millisecond // 单个翻译任务完成后的延迟
+	batchDelay = 1 * time.Second        // 批次之间的延迟
+)
+
+// translateGroup 合并缓存键和配置都相同的并发翻译请求，见 translateShared
+var translateGroup singleflight.Group
+
+// Translate 是一个基本的翻译函数
+func Translate(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
+	// 验证输入
+	if text == "" {
+		return "", ErrEmptyText
+	}
+	if inputLanguage == "" {
+		return "", ErrEmptyInputLanguage
+	}
+	if outputLanguage == "" {
+		return "", ErrEmptyOutputLanguage
+	}
+
+	o := newOptions(opts)
+	ctx, cancel := o.withDeadline(ctx)
+	defer cancel()
 
-type cacheEntry struct {
-	result    string
-	timestamp time.Time
+	// 整段只是网址、邮箱或代码时不翻译，原样返回调用方的输入并作为译文缓存
+	if o.skipUntranslatable(text) {
+		o.cacheSet(ctx, o.cacheNormalization.apply(text), inputLanguage, outputLanguage, text)
+		return text, nil
+	}
+
+	if o.scriptCheck {
+		if err := checkScript(text, inputLanguage); err != nil {
+			log.Printf("Script check failed for '%s': %v", text, err)
+			return "", err
+		}
+	}
+	if o.shouldMock(llm, nil) {
+		return o.translateMock(ctx, text)
+	}
+	// 发给模型的文本先统一 Unicode 规范化形式，缓存键由 cacheKey 统一规范化
+	source := o.normalizeInput(text)
+	cacheText := o.cacheNormalization.apply(source)
+	key := o.cacheKey(ctx, cacheText, inputLanguage, outputLanguage)
+
+	// 检查缓存；开启 stale-while-revalidate 时过期条目立即返回，同时在后台刷新
+	if result, stale, ok := o.translationCache().lookupKey(key); ok {
+		log.Printf("Cache hit for text: %s", text)
+		if stale {
+			o.revalidate(ctx, llm, source, cacheText, inputLanguage, outputLanguage, key)
+		}
+		return result, nil
+	}
+	if result, ok := o.fuzzyGet(ctx, o.translationCache(), cacheText, inputLanguage, outputLanguage); ok {
+		return result, nil
+	}
+
+	// 缓存键和配置都相同的并发请求合并为一次调用，共享同一个结果
+	out, err := translateShared(ctx, llm, source, cacheText, inputLanguage, outputLanguage, key, o)
+	if err != nil && o.shouldMock(llm, err) {
+		return o.translateMock(ctx, text)
+	}
+	return out, err
 }
 
-var (
-	defaultCache = &TranslationCache{
-		cache: make(map[string]cacheEntry),
+// translateUncached 完成一次未命中缓存的翻译（含重新提示、质量评估和译后编辑），成功后写入缓存
+func translateUncached(ctx context.Context, llm llms.Model, text string, cacheText string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	// 等待期间其他请求可能已经写入缓存
+	if result, ok := o.translationCache().getKey(o.cacheKey(ctx, cacheText, inputLanguage, outputLanguage)); ok {
+		return result, nil
+	}
+
+	// 按语言对限流，缓存命中的请求不占用额度
+	if err := waitRateLimit(ctx, inputLanguage, outputLanguage); err != nil {
+		return "", err
+	}
+
+	out, cacheable, err := translateChecked(ctx, llm, text, inputLanguage, outputLanguage, o)
+	if out == "" {
+		return "", err
+	}
+	out = o.postEditText(out, inputLanguage, outputLanguage)
+	if err != nil || !cacheable {
+		return out, err
 	}
-)
 
-// getCacheKey 生成缓存键
-func getCacheKey(text, inputLang, outputLang string) string {
-	return fmt.Sprintf("%s:%s:%s", text, inputLang, outputLang)
+	// 缓存结果
+	o.cacheSet(ctx, cacheText, inputLanguage, outputLanguage, out)
+	return out, nil
 }
 
-// Get 从缓存获取翻译结果
-func (c *TranslationCache) Get(text, inputLang, outputLang string) (string, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// translateChecked 翻译并依次做可疑输出重新提示、严格输出清理和质量评估，
+// 返回译文以及它是否可以写入缓存；质量不达标时同时返回译文和错误
+func translateChecked(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options) (string, bool, error) {
+	out, err := translateOnce(ctx, llm, text, inputLanguage, outputLanguage, o)
+	if err != nil {
+		return "", false, err
+	}
+
+	// 回显保护：开启后原样返回原文视为失败，重试仍回显则返回 ErrEcho
+	out, err = guardEcho(ctx, llm, text, out, inputLanguage, outputLanguage, o)
+	if err != nil {
+		return "", false, err
+	}
 
-	key := getCacheKey(text, inputLang, outputLang)
-	if entry, ok := c.cache[key]; ok {
-		if time.Since(entry.timestamp) < cacheDuration {
-			return entry.result, true
+	// 输出原样回显或带有解释时，用更严格的指令重新翻译一次；仍可疑或重试预算用完时返回结果但不缓存
+	if isSuspiciousOutput(text, out, inputLanguage, outputLanguage) {
+		if !SpendRetry(ctx) {
+			return out, false, nil
+		}
+		log.Printf("Suspicious translation output for '%s': %s, reprompting", text, out)
+		out, err = translateStrict(ctx, llm, text, inputLanguage, outputLanguage, o, o.retryOptions(1)...)
+		if err != nil {
+			return "", false, err
+		}
+		if isSuspiciousOutput(text, out, inputLanguage, outputLanguage) {
+			log.Printf("Translation output still suspicious after reprompt: %s", out)
+			return out, false, nil
+		}
+	}
+
+	// 严格输出：清理附带的解释，无法清理时返回结果但不缓存
+	out, ok, err := enforceOutputOnly(ctx, llm, text, out, inputLanguage, outputLanguage, o)
+	if err != nil {
+		return "", false, err
+	}
+	if !ok {
+		return out, false, nil
+	}
+
+	// 质量评估：低于阈值时重新翻译，仍不达标则返回结果并标记错误，且不写入缓存
+	if o.qualityThreshold > 0 {
+		out, err = ensureQuality(ctx, llm, text, out, inputLanguage, outputLanguage, o)
+		if err != nil {
+			return out, false, err
+		}
+	}
+	return out, true, nil
+}
+
+// translateOnce 调用一次 LLM 完成翻译，不经过缓存
+func translateOnce(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options) (string, error) {
+	// 优先使用为该语言对注册的模板，疑似提示注入时使用分隔模板
+	values := map[string]any{
+		"inputLanguage":  inputLanguage,
+		"outputLanguage": outputLanguage,
+		"text":           text,
+	}
+	template := o.withHint(o.withOutputInstruction(o.withInjectionGuard(promptFor(inputLanguage, outputLanguage), values), values), values)
+
+	// 模型偶尔返回 200 但内容为空，重试一次后仍为空则返回 ErrEmptyResponse
+	for attempt := 0; ; attempt++ {
+		choices, err := runPromptChoices(ctx, llm, o, template, values, o.retryOptions(attempt)...)
+		if err != nil {
+			// 记录详细错误信息，帮助定位 OpenAI API 返回 400 错误的原因
+			log.Printf("OpenAI API 调用失败（状态码 %d），详细错误信息: %v", StatusCode(err), err)
+			return "", fmt.Errorf("translation failed: %w", err)
+		}
+		out, err := o.parse(choices[0])
+		if err != nil {
+			return "", err
 		}
-		// 清理过期缓存
-		delete(c.cache, key)
+		if strings.TrimSpace(out) != "" {
+			return out, nil
+		}
+		if attempt >= emptyResponseRetries || !SpendRetry(ctx) {
+			return "", fmt.Errorf("translation failed: %w", ErrEmptyResponse)
+		}
+		log.Printf("Empty translation output for '%s', retrying", text)
 	}
-	return "", false
 }
 
-// Set 设置缓存
-func (c *TranslationCache) Set(text, inputLang, outputLang, result string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// WithSystemPrompt 设置以 system 角色发送的提示词，用于给模型设定一致的人设，
+// 例如 "You are a professional literary translator"
+func WithSystemPrompt(s string) Option {
+	return func(o *options) {
+		o.systemPrompt = s
+	}
+}
 
-	key := getCacheKey(text, inputLang, outputLang)
-	c.cache[key] = cacheEntry{
-		result:    result,
-		timestamp: time.Now(),
+// WithMaxTokens 限制模型单次输出的最大 token 数，防止模型在译文之外继续输出
+func WithMaxTokens(n int) Option {
+	return func(o *options) {
+		o.maxTokens = n
 	}
 }
 
-// Translate 是一个基本的翻译函数
-func Translate(ctx context.Context, llm *openai.LLM, text string, inputLanguage string, outputLanguage string) (string, error) {
-	// 验证输入
-	if text == "" {
-		return "", fmt.Errorf("empty text input")
+// WithStopWords 设置停止标记，模型输出这些标记时立即停止
+func WithStopWords(words []string) Option {
+	return func(o *options) {
+		o.stopWords = words
 	}
-	if inputLanguage == "" {
-		return "", fmt.Errorf("empty input language")
+}
+
+// callOptions 把配置和模型选择（WithModel 或 context 中的覆盖）转换为模型调用选项
+func (o *options) callOptions(ctx context.Context) []llms.CallOption {
+	var callOpts []llms.CallOption
+	if model, ok := o.modelName(ctx); ok {
+		callOpts = append(callOpts, llms.WithModel(model))
 	}
-	if outputLanguage == "" {
-		return "", fmt.Errorf("empty output language")
+	if o.maxTokens > 0 {
+		callOpts = append(callOpts, llms.WithMaxTokens(o.maxTokens))
 	}
+	if len(o.stopWords) > 0 {
+		callOpts = append(callOpts, llms.WithStopWords(o.stopWords))
+	}
+	return callOpts
+}
 
-	// 检查缓存
-	if result, ok := defaultCache.Get(text, inputLanguage, outputLanguage); ok {
-		log.Printf("Cache hit for text: %s", text)
-		return result, nil
+// newRequestError 用本次请求的概要包装被提供方拒绝的错误，只记录长度等信息，不包含原文
+func newRequestError(ctx context.Context, o *options, values map[string]any, messages []llms.MessageContent, err error) *RequestError {
+	reqErr := &RequestError{MaxTokens: o.maxTokens, Err: err}
+	reqErr.Model, _ = o.modelName(ctx)
+	reqErr.InputLanguage, _ = values["inputLanguage"].(string)
+	reqErr.OutputLanguage, _ = values["outputLanguage"].(string)
+	if text, ok := values["text"].(string); ok {
+		reqErr.TextLength = utf8.RuneCountInString(text)
+	}
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if tc, ok := part.(llms.TextContent); ok {
+				reqErr.PromptLength += utf8.RuneCountInString(tc.Text)
+			}
+		}
+	}
+	return reqErr
+}
+
+// runPrompt 用给定的模板和变量生成用户消息（配置了系统提示词时在前面加上 system 消息），
+// 调用一次模型并返回输出的文本
+func runPrompt(ctx context.Context, llm llms.Model, o *options, template string, values map[string]any) (string, error) {
+	choices, err := runPromptChoices(ctx, llm, o, template, values)
+	if err != nil {
+		return "", err
+	}
+	return choices[0], nil
+}
+
+// formatPrompt 用 values 中的全部变量渲染提示词模板
+func formatPrompt(template string, values map[string]any) (string, error) {
+	inputVariables := make([]string, 0, len(values))
+	for name := range values {
+		inputVariables = append(inputVariables, name)
+	}
+	text, err := prompts.NewPromptTemplate(template, inputVariables).Format(values)
+	if err != nil {
+		return "", fmt.Errorf("format prompt: %w", err)
+	}
+	return text, nil
+}
+
+// runPromptChoices 与 runPrompt 相同，但可以追加调用选项，并返回模型给出的全部候选回复
+func runPromptChoices(ctx context.Context, llm llms.Model, o *options, template string, values map[string]any, extra ...llms.CallOption) ([]string, error) {
+	// 语言参数是 BCP-47 标签时展开为带地区的名称，如 zh-TW -> Traditional Chinese (zh-TW)
+	rawValues := values
+	values = describeLanguageValues(values)
+
+	text, err := formatPrompt(template, values)
+	if err != nil {
+		return nil, err
 	}
 
-	// 优化的 prompt 模板
-	prompt := prompts.NewPromptTemplate(
-		`Translate "{{.text}}" from {{.inputLanguage}} to {{.outputLanguage}}. Output the translation only, no explanations.`,
-		[]string{"inputLanguage", "outputLanguage", "text"},
-	)
+	messages := make([]llms.MessageContent, 0, 2)
+	if o.systemPrompt != "" {
+		messages = append(messages, llms.TextParts(llms.ChatMessageTypeSystem, o.systemPrompt))
+	}
+	messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, text))
 
-	llmChain := chains.NewLLMChain(llm, prompt)
+	// 时间预算已经用完时不再发起新的调用，避免重试叠加超过调用方的截止时间
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// 设置超时
-	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	// 等待全局并发额度，所有调用共享同一个上限
+	release, err := acquireGlobal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wait for concurrency slot: %w", err)
+	}
+	defer release()
+
+	if o.callbacks != nil {
+		// 包装模型以便观察 LLM 调用，并在整次调用前后触发 chain 级别的回调
+		llm = callbackModel{Model: llm, handler: o.callbacks}
+		o.callbacks.HandleChainStart(ctx, values)
+	}
+
+	// 单次调用的超时不超过剩余的时间预算
+	timeoutCtx, cancel := attemptContext(ctx)
 	defer cancel()
 
-	outputValues, err := chains.Call(timeoutCtx, llmChain, map[string]any{
-		"inputLanguage":  inputLanguage,
-		"outputLanguage": outputLanguage,
-		"text":           text,
-	})
+	resp, err := llm.GenerateContent(timeoutCtx, messages, append(o.callOptions(ctx), extra...)...)
+	if err == nil && len(resp.Choices) == 0 {
+		err = fmt.Errorf("empty response from model")
+	}
 	if err != nil {
-		// 记录详细错误信息，帮助定位 OpenAI API 返回 400 错误的原因
-		log.Printf("OpenAI API 调用失败，详细错误信息: %v", err)
-		return "", fmt.Errorf("translation failed: %w", err)
+		if o.callbacks != nil {
+			o.callbacks.HandleChainError(ctx, err)
+		}
+		err = fmt.Errorf("%w: %w", ErrUpstream, ClassifyError(err))
+		if errors.Is(err, ErrBadRequest) {
+			err = newRequestError(ctx, o, rawValues, messages, err)
+		}
+		return nil, err
 	}
 
-	out, ok := outputValues[llmChain.OutputKey].(string)
-	if !ok {
-		return "", fmt.Errorf("invalid chain return")
+	if o.costTracker != nil {
+		o.costTracker.add(resp.Choices[0].GenerationInfo)
 	}
 
-	// 缓存结果
-	defaultCache.Set(text, inputLanguage, outputLanguage, out)
-	return out, nil
+	choices := make([]string, len(resp.Choices))
+	for i, choice := range resp.Choices {
+		choices[i] = strings.TrimSpace(choice.Content)
+	}
+	if o.callbacks != nil {
+		o.callbacks.HandleChainEnd(ctx, map[string]any{"text": choices[0]})
+	}
+	return choices, nil
 }
 
-// TranslateBatch 批量翻译文本
-func TranslateBatch(ctx context.Context, llm *openai.LLM, texts []string, inputLanguage string, outputLanguage string) ([]string, error) {
-	if len(texts) == 0 {
-		return nil, fmt.Errorf("empty texts input")
+// FailureFallback 指定批量翻译中单条失败时的处理方式
+type FailureFallback int
+
+const (
+	// FallbackStrict 任一条翻译失败即中止并返回错误（默认）
+	FallbackStrict FailureFallback = iota
+	// FallbackKeepOriginal 翻译失败的条目保留原文并在结果中标记，不中止整批
+	FallbackKeepOriginal
+	// FallbackPartial 在第一处失败时停止，同时返回已经翻译的部分和错误；
+	// 只对 TranslateLongText 有意义，批量翻译按 FallbackStrict 处理
+	FallbackPartial
+)
+
+// WithFailureFallback 设置批量翻译中单条失败时的处理方式
+func WithFailureFallback(f FailureFallback) Option {
+	return func(o *options) {
+		o.failureFallback = f
 	}
+}
 
-	results := make([]string, len(texts))
-	errChan := make(chan error, len(texts))
-	var wg sync.WaitGroup
+// BatchResult 是批量翻译中单条文本的结果
+type BatchResult struct {
+	Index     int    // 在输入 texts 中的下标
+	Text      string // 译文；Fallback 为 true 时为原文
+	Fallback  bool   // 翻译失败并回退为原文
+	Err       error  // 导致失败的错误：回退时为回退原因，TranslateBatchStream 中为该条的翻译错误
+	Romanized string // 译文的罗马字转写（如拼音、罗马字），仅在 WithRomanization 时填充
+}
 
-	// 限制并发数
-	semaphore := make(chan struct{}, maxConcurrency)
+// TranslateBatch 批量翻译文本，返回的译文与 texts 按下标一一对应
+func TranslateBatch(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]string, error) {
+	results, err := TranslateBatchResults(ctx, llm, texts, inputLanguage, outputLanguage, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]string, len(results))
+	for i, result := range results {
+		translations[i] = result.Text
+	}
+	return translations, nil
+}
+
+// batchItem 是工作 goroutine 回传的单条结果，带有原始下标
+type batchItem struct {
+	index  int
+	result BatchResult
+	err    error
 
-	// 分批处理
-	for i := 0; i < len(texts); i += batchSize {
-		end := i + batchSize
-		if end > len(texts) {
-			end = len(texts)
+	duration time.Duration // 处理这一条的耗时，供 TranslateBatchWithMetrics 使用
+}
+
+// runBatches 是批量翻译的工作循环：按 batchSize 把 pending 中的下标分批，每条一个工作 goroutine 调用 work，
+// 并发数受 semaphore 限制，批次之间等待 batchDelay。工作 goroutine 把结果发到通道，
+// 由调用方 goroutine 对每条结果调用 collect；collect 返回错误时仍收齐本批结果，避免遗留 goroutine，
+// 然后不再开始后续批次，返回本批第一个错误
+func runBatches(pending []int, semaphore chan struct{}, workers *sync.WaitGroup, work func(index int) batchItem, collect func(item batchItem) error) error {
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
 		}
 
-		batch := texts[i:end]
-		for j, text := range batch {
-			wg.Add(1)
-			go func(index, batchIndex int, text string) {
-				defer wg.Done()
+		// 通道容量等于批次大小，工作 goroutine 发送时不会阻塞
+		items := make(chan batchItem, end-start)
+		for _, index := range pending[start:end] {
+			workers.Add(1)
+			go func(index int) {
+				defer workers.Done()
 
 				// 获取信号量
 				semaphore <- struct{}{}
 				defer func() { <-semaphore }()
 
-				// 检查缓存
-				if result, ok := defaultCache.Get(text, inputLanguage, outputLanguage); ok {
-					results[index] = result
-					return
-				}
-
-				// 为每个翻译任务设置独立的超时
-				taskCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
-				defer cancel()
+				items <- work(index)
+			}(index)
+		}
 
-				result, err := Translate(taskCtx, llm, text, inputLanguage, outputLanguage)
-				if err != nil {
-					errChan <- fmt.Errorf("failed to translate text at index %d: %w", index, err)
-					return
-				}
-				results[index] = result
+		var firstErr error
+		for n := start; n < end; n++ {
+			if err := collect(<-items); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
 
-				// 添加延迟以避免 API 限制
-				time.Sleep(500 * time.Millisecond)
-			}(i+j, i/batchSize, text)
+		// 批次间添加延迟以避免 API 限制
+		if end < len(pending) {
+			time.Sleep(batchDelay)
 		}
+	}
+	return nil
+}
+
+// TranslateBatchResults 与 TranslateBatch 相同，但返回每条文本的详细结果，
+// 配合 WithFailureFallback(FallbackKeepOriginal) 可以知道哪些条目回退成了原文。
+//
+// 顺序保证：无论各条翻译以什么顺序完成，results[i] 始终对应 texts[i]。
+// 工作 goroutine 不直接写共享的结果切片，而是把带下标的结果发到通道，由调用方 goroutine 统一归位
+func TranslateBatchResults(ctx context.Context, llm llms.Model, texts []string, inputLanguage string, outputLanguage string, opts ...Option) ([]BatchResult, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("empty texts input")
+	}
 
-		// 等待当前批次完成
-		wg.Wait()
+	o := newOptions(opts)
+	results := make([]BatchResult, len(texts))
 
-		// 检查错误
-		select {
-		case err := <-errChan:
-			close(errChan)
-			return nil, fmt.Errorf("batch translation error: %v", err)
-		default:
-			// 没有错误，继续处理
+	// 一次性查缓存，只把未命中的文本交给工作 goroutine
+	hits := o.cacheGetMany(ctx, llm, texts, inputLanguage, outputLanguage)
+	var pending []int
+	for i := range texts {
+		if result, ok := hits[i]; ok {
+			results[i] = BatchResult{Index: i, Text: result}
+			continue
 		}
+		pending = append(pending, i)
+	}
 
-		// 批次间添加延迟以避免 API 限制
-		if end < len(texts) {
-			time.Sleep(1 * time.Second)
+	// 限制并发数；workers 跟踪工作 goroutine，供 WithStrictConcurrencyChecks 检查
+	semaphore := make(chan struct{}, maxConcurrency)
+	var workers sync.WaitGroup
+
+	// 分批处理，按下标归位
+	err := runBatches(pending, semaphore, &workers, func(index int) batchItem {
+		result, err := translateBatchItem(ctx, llm, texts[index], inputLanguage, outputLanguage, o, opts)
+		result.Index = index
+		return batchItem{index: index, result: result, err: err}
+	}, func(item batchItem) error {
+		if item.err != nil {
+			return fmt.Errorf("failed to translate text at index %d: %w", item.index, item.err)
 		}
+		results[item.index] = item.result
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("batch translation error: %w", err), o.checkConcurrency(semaphore, &workers))
+	}
+
+	if err := o.checkConcurrency(semaphore, &workers); err != nil {
+		return nil, err
 	}
 
+	if o.romanization {
+		if err := romanizeResults(ctx, llm, results, outputLanguage, o); err != nil {
+			return nil, fmt.Errorf("batch translation error: %w", err)
+		}
+	}
 	return results, nil
 }
 
+// translateBatchItem 翻译批量中未命中缓存的一条文本：跳过已是目标语言的文本，
+// 并按 FailureFallback 处理失败
+func translateBatchItem(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, o *options, opts []Option) (BatchResult, error) {
+	// 为每个翻译任务设置独立的超时
+	taskCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	// 已经是目标语言的文本原样保留
+	if alreadyInLanguage(taskCtx, llm, text, outputLanguage, o) {
+		return BatchResult{Text: text}, nil
+	}
+
+	result, err := Translate(taskCtx, llm, text, inputLanguage, outputLanguage, opts...)
+	if err != nil {
+		if o.failureFallback == FallbackKeepOriginal {
+			log.Printf("Translation failed, keeping original '%s': %v", text, err)
+			return BatchResult{Text: text, Fallback: true, Err: err}, nil
+		}
+		return BatchResult{}, err
+	}
+
+	// 添加延迟以避免 API 限制
+	time.Sleep(itemDelay)
+	return BatchResult{Text: result}, nil
+}
+
 // TranslateWithTool 使用 LangChain 工具进行翻译
-func TranslateWithTool(ctx context.Context, llm *openai.LLM, text string, inputLanguage string, outputLanguage string) (string, error) {
+func TranslateWithTool(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (string, error) {
 	// 验证输入
 	if text == "" {
-		return "", fmt.Errorf("empty text input")
+		return "", ErrEmptyText
 	}
 	if inputLanguage == "" {
-		return "", fmt.Errorf("empty input language")
+		return "", ErrEmptyInputLanguage
 	}
 	if outputLanguage == "" {
-		return "", fmt.Errorf("empty output language")
-	}
-
-	// 检查缓存
-	if result, ok := defaultCache.Get(text, inputLanguage, outputLanguage); ok {
-		log.Printf("Cache hit for text: %s", text)
-		return result, nil
+		return "", ErrEmptyOutputLanguage
 	}
 
+	// 缓存由工具内部的 Translate 按完整的缓存键（提示、命名空间、WithNoCache 等）读写
+	o := newOptions(opts)
 	log.Printf("Starting translation with tool: '%s' from %s to %s", text, inputLanguage, outputLanguage)
 
-	// 设置超时
-	timeoutCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	// 设置超时：总预算来自 WithTimeout 或调用方的截止时间，单次调用不超过剩余预算
+	ctx, cancel := o.withDeadline(ctx)
 	defer cancel()
+	timeoutCtx, cancelAttempt := attemptContext(ctx)
+	defer cancelAttempt()
 
 	translator := NewTranslator(llm)
+	translator.Options = opts
 	inputText := fmt.Sprintf("Translate '%s' from %s to %s. Output the translation only.", text, inputLanguage, outputLanguage)
 	result, err := translator.Call(timeoutCtx, inputText)
 	if err != nil {
@@ -229,8 +558,6 @@ func TranslateWithTool(ctx context.Context, llm *openai.LLM, text string, inputL
 		// return Translate(ctx, llm, text, inputLanguage, outputLanguage)
 	}
 
-	// 缓存结果
-	defaultCache.Set(text, inputLanguage, outputLanguage, result)
 	log.Printf("Tool translation successful: %s", result)
 	return result, nil
 }
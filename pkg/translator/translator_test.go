@@ -2,6 +2,7 @@ package translator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,9 +11,80 @@ import (
 	"testing"
 	"time"
 
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
+// fakeLLM 是用于测试的 llms.Model 实现，记录每次调用的消息、prompt 和调用选项，
+// 并通过 respond 返回预设的结果
+type fakeLLM struct {
+	mu       sync.Mutex
+	respond  func(prompt string) (string, error)
+	info     map[string]any // 每次回复附带的 GenerationInfo
+	prompts  []string
+	messages [][]llms.MessageContent
+	options  []llms.CallOptions
+}
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var parts []string
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			if tc, ok := p.(llms.TextContent); ok {
+				parts = append(parts, tc.Text)
+			}
+		}
+	}
+	prompt := strings.Join(parts, "\n")
+
+	var opts llms.CallOptions
+	for _, o := range options {
+		o(&opts)
+	}
+
+	f.mu.Lock()
+	f.prompts = append(f.prompts, prompt)
+	f.messages = append(f.messages, messages)
+	f.options = append(f.options, opts)
+	respond := f.respond
+	f.mu.Unlock()
+
+	out, err := respond(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: out, GenerationInfo: f.info}}}, nil
+}
+
+func (f *fakeLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// Calls 返回 fakeLLM 被调用的次数
+func (f *fakeLLM) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.prompts)
+}
+
+// newDictLLM 创建一个按词典翻译的 fakeLLM：返回 prompt 中出现的最长原文对应的译文
+func newDictLLM(dict map[string]string) *fakeLLM {
+	return &fakeLLM{
+		respond: func(prompt string) (string, error) {
+			best := ""
+			for src := range dict {
+				if len(src) > len(best) && strings.Contains(prompt, src) {
+					best = src
+				}
+			}
+			if best == "" {
+				return "", fmt.Errorf("unexpected prompt: %s", prompt)
+			}
+			return dict[best], nil
+		},
+	}
+}
+
 func setupLLM(t *testing.T) *openai.LLM {
 	apiKey := os.Getenv("SILICONFLOW_API_KEY")
 	if apiKey == "" {
@@ -183,6 +255,370 @@ func TestTranslate(t *testing.T) {
 	}
 }
 
+// TestTranslate_WithSystemPrompt 测试系统提示词以 system 角色消息发送
+func TestTranslate_WithSystemPrompt(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	persona := "You are a professional literary translator"
+
+	if _, err := Translate(context.Background(), llm, "Hello", "English", "Chinese", WithSystemPrompt(persona)); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if len(llm.messages) != 1 {
+		t.Fatalf("expected 1 LLM call, got %d", len(llm.messages))
+	}
+	messages := llm.messages[0]
+	if len(messages) != 2 {
+		t.Fatalf("expected system and human messages, got %d messages", len(messages))
+	}
+	if messages[0].Role != llms.ChatMessageTypeSystem {
+		t.Errorf("first message role = %s, want %s", messages[0].Role, llms.ChatMessageTypeSystem)
+	}
+	if got := messages[0].Parts[0].(llms.TextContent).Text; got != persona {
+		t.Errorf("system message = %q, want %q", got, persona)
+	}
+	if messages[1].Role != llms.ChatMessageTypeHuman {
+		t.Errorf("second message role = %s, want %s", messages[1].Role, llms.ChatMessageTypeHuman)
+	}
+
+	// 未设置系统提示词时只发送用户消息
+	defaultCache.Clear()
+	plain := newDictLLM(map[string]string{"Hello": "你好"})
+	if _, err := Translate(context.Background(), plain, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if len(plain.messages[0]) != 1 || plain.messages[0][0].Role != llms.ChatMessageTypeHuman {
+		t.Errorf("expected a single human message without system prompt, got %+v", plain.messages[0])
+	}
+}
+
+// TestTranslate_CallOptions 测试最大 token 数和停止标记传递到模型调用选项
+func TestTranslate_CallOptions(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+
+	_, err := Translate(context.Background(), llm, "Hello", "English", "Chinese",
+		WithMaxTokens(64), WithStopWords([]string{"\n\n", "Note:"}))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	opts := llm.options[0]
+	if opts.MaxTokens != 64 {
+		t.Errorf("MaxTokens = %d, want 64", opts.MaxTokens)
+	}
+	if len(opts.StopWords) != 2 || opts.StopWords[0] != "\n\n" || opts.StopWords[1] != "Note:" {
+		t.Errorf("StopWords = %q, want [\"\\n\\n\" \"Note:\"]", opts.StopWords)
+	}
+
+	// 未设置时不传递调用选项
+	defaultCache.Clear()
+	plain := newDictLLM(map[string]string{"Hello": "你好"})
+	if _, err := Translate(context.Background(), plain, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if plain.options[0].MaxTokens != 0 || len(plain.options[0].StopWords) != 0 {
+		t.Errorf("expected no call options by default, got %+v", plain.options[0])
+	}
+}
+
+// TestTranslate_ModelOverride 测试 context 中的模型覆盖传递到模型调用选项
+func TestTranslate_ModelOverride(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好", "Bye": "再见"})
+
+	ctx := WithModelOverride(context.Background(), "cheap-model")
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := llm.options[0].Model; got != "cheap-model" {
+		t.Errorf("Model = %q, want %q", got, "cheap-model")
+	}
+
+	// 没有覆盖时不设置模型，使用客户端的默认模型
+	if _, err := Translate(context.Background(), llm, "Bye", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := llm.options[1].Model; got != "" {
+		t.Errorf("Model = %q, want empty without override", got)
+	}
+}
+
+// TestTranslate_WithModel 测试 WithModel 只对本次翻译生效，并优先于 context 中的覆盖
+func TestTranslate_WithModel(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好", "Bye": "再见"})
+
+	ctx := WithModelOverride(context.Background(), "cheap-model")
+	if _, err := Translate(ctx, llm, "Hello", "English", "Chinese", WithModel("strong-model")); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := llm.options[0].Model; got != "strong-model" {
+		t.Errorf("Model = %q, want %q", got, "strong-model")
+	}
+
+	if _, err := Translate(ctx, llm, "Bye", "English", "Chinese"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := llm.options[1].Model; got != "cheap-model" {
+		t.Errorf("Model = %q, want %q without WithModel", got, "cheap-model")
+	}
+}
+
+// TestTranslate_ModelCacheKey 测试不同模型的译文分开缓存，同一模型的重复请求命中缓存
+func TestTranslate_ModelCacheKey(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好"})
+	ctx := context.Background()
+
+	calls := []struct {
+		ctx  context.Context
+		opts []Option
+	}{
+		{ctx, nil},
+		{ctx, []Option{WithModel("gpt-4o")}},
+		{WithModelOverride(ctx, "cheap-model"), nil},
+		{ctx, []Option{WithModel("gpt-4o")}}, // 与第二次相同，命中缓存
+	}
+	for _, c := range calls {
+		if _, err := Translate(c.ctx, llm, "Hello", "English", "Chinese", c.opts...); err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("LLM called %d times, want 3 (one per model)", llm.Calls())
+	}
+	if got := llm.options[1].Model; got != "gpt-4o" {
+		t.Errorf("second call used model %q, want %q", got, "gpt-4o")
+	}
+}
+
+// TestTranslate_EmptyResponseRetry 测试模型返回空内容时重试一次，且空结果不写入缓存
+func TestTranslate_EmptyResponseRetry(t *testing.T) {
+	ctx := context.Background()
+	defaultCache.Clear()
+
+	replies := []string{"  \n", "你好"}
+	llm := &fakeLLM{}
+	llm.respond = func(prompt string) (string, error) {
+		reply := replies[0]
+		replies = replies[1:]
+		if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+			t.Error("empty output was cached before the retry")
+		}
+		return reply, nil
+	}
+
+	result, err := Translate(ctx, llm, "Hello", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if result != "你好" {
+		t.Errorf("Translate() = %q, want %q", result, "你好")
+	}
+	if n := llm.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+
+	// 重试后仍为空：返回 ErrEmptyResponse，不写入缓存
+	defaultCache.Clear()
+	empty := &fakeLLM{respond: func(prompt string) (string, error) { return "", nil }}
+	_, err = Translate(ctx, empty, "Hello", "English", "Chinese")
+	if !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("Translate() error = %v, want ErrEmptyResponse", err)
+	}
+	if n := empty.Calls(); n != 2 {
+		t.Errorf("LLM called %d times, want 2", n)
+	}
+	if _, ok := defaultCache.Get("Hello", "English", "Chinese"); ok {
+		t.Error("empty output should never be cached")
+	}
+}
+
+// TestTranslate_Singleflight 测试并发翻译同一文本时只调用一次模型
+func TestTranslate_Singleflight(t *testing.T) {
+	defaultCache.Clear()
+	release := make(chan struct{})
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		<-release
+		return "你好", nil
+	}}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	results := make([]string, workers)
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = Translate(context.Background(), llm, "Hello", "English", "Chinese")
+		}(i)
+	}
+
+	// 让所有 goroutine 有机会进入等待后再放行模型调用
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		if errs[i] != nil || results[i] != "你好" {
+			t.Errorf("worker %d: Translate() = %q, %v", i, results[i], errs[i])
+		}
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("expected exactly 1 LLM call, got %d", llm.Calls())
+	}
+}
+
+// TestTranslateBatch_OrderStress 在乱序完成的情况下验证结果顺序，需配合 -race 运行
+func TestTranslateBatch_OrderStress(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+
+	const n = 200
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("item-%03d", i)
+	}
+	// 按编号返回译文，并随机延迟让各条以不同顺序完成
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		for i := len(texts) - 1; i >= 0; i-- {
+			if strings.Contains(prompt, texts[i]) {
+				time.Sleep(time.Duration(i%7) * time.Millisecond)
+				return fmt.Sprintf("译文-%03d", i), nil
+			}
+		}
+		return "", fmt.Errorf("unexpected prompt: %s", prompt)
+	}}
+
+	got, err := TranslateBatch(context.Background(), llm, texts, "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if len(got) != n {
+		t.Fatalf("expected %d results, got %d", n, len(got))
+	}
+	for i, result := range got {
+		if want := fmt.Sprintf("译文-%03d", i); result != want {
+			t.Errorf("result[%d] = %q, want %q", i, result, want)
+		}
+	}
+}
+
+// TestTranslate_SingleflightOwnContext 测试合并的调用方各自按自己的 context 等待，一个调用方超时不影响另一个
+func TestTranslate_SingleflightOwnContext(t *testing.T) {
+	defaultCache.Clear()
+	release := make(chan struct{})
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		<-release
+		return "你好", nil
+	}}
+
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	shortErr := make(chan error, 1)
+	go func() {
+		_, err := Translate(short, llm, "Hello", "English", "Chinese")
+		shortErr <- err
+	}()
+
+	// 等第一个调用方发起模型调用后再加入第二个调用方
+	for llm.Calls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	longResult := make(chan string, 1)
+	go func() {
+		got, err := Translate(context.Background(), llm, "Hello", "English", "Chinese")
+		if err != nil {
+			t.Errorf("caller without deadline: Translate() error = %v", err)
+		}
+		longResult <- got
+	}()
+
+	if err := <-shortErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("caller with deadline: Translate() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if got := <-longResult; got != "你好" {
+		t.Errorf("caller without deadline: Translate() = %q, want %q", got, "你好")
+	}
+	if llm.Calls() != 1 {
+		t.Errorf("expected the callers to share 1 LLM call, got %d", llm.Calls())
+	}
+}
+
+// TestTranslate_SingleflightDifferentOptions 测试配置不同的并发请求不共享结果
+func TestTranslate_SingleflightDifferentOptions(t *testing.T) {
+	defaultCache.Clear()
+	release := make(chan struct{})
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		<-release
+		return "你好", nil
+	}}
+	exclaim := WithPostEdit(func(target, inputLanguage, outputLanguage string) string { return target + "！" })
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i, opts := range [][]Option{nil, {exclaim}, {WithModel("gpt-4o")}} {
+		wg.Add(1)
+		go func(i int, opts []Option) {
+			defer wg.Done()
+			var err error
+			if results[i], err = Translate(context.Background(), llm, "Hello", "English", "Chinese", opts...); err != nil {
+				t.Errorf("caller %d: Translate() error = %v", i, err)
+			}
+		}(i, opts)
+	}
+	for llm.Calls() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if results[1] != "你好！" {
+		t.Errorf("caller with WithPostEdit got %q, want the edited %q", results[1], "你好！")
+	}
+	if llm.Calls() != 3 {
+		t.Errorf("expected 3 LLM calls for 3 different configurations, got %d", llm.Calls())
+	}
+}
+
+// TestTranslateBatch_FailureFallback 测试单条失败时保留原文并标记
+func TestTranslateBatch_FailureFallback(t *testing.T) {
+	withoutBatchDelay(t)
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{"Hello": "你好", "Thank you": "谢谢"})
+	texts := []string{"Hello", "Untranslatable", "Thank you"}
+
+	// 默认严格模式：任一条失败即返回错误
+	if _, err := TranslateBatch(context.Background(), llm, texts, "English", "Chinese"); err == nil {
+		t.Fatal("expected error in strict mode")
+	}
+
+	results, err := TranslateBatchResults(context.Background(), llm, texts, "English", "Chinese", WithFailureFallback(FallbackKeepOriginal))
+	if err != nil {
+		t.Fatalf("TranslateBatchResults() error = %v", err)
+	}
+	if results[1].Text != "Untranslatable" || !results[1].Fallback || results[1].Err == nil {
+		t.Errorf("failed item = %+v, want original text flagged as fallback", results[1])
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Fallback {
+			t.Errorf("item %d unexpectedly marked as fallback: %+v", i, results[i])
+		}
+	}
+
+	got, err := TranslateBatch(context.Background(), llm, texts, "English", "Chinese", WithFailureFallback(FallbackKeepOriginal))
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"你好", "Untranslatable", "谢谢"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("TranslateBatch() = %q, want %q", got, want)
+	}
+}
+
 // TestTranslateWithTool 测试工具翻译功能
 func TestTranslateWithTool(t *testing.T) {
 	llm := setupLLM(t)
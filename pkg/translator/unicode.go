@@ -0,0 +1,19 @@
+package translator
+
+import "golang.org/x/text/unicode/norm"
+
+// WithNormalizeUnicode 设置翻译前是否把输入文本规范化为 Unicode NFC 形式（默认开启）。
+// 开启后预组合字符与组合字符序列（如 U+00E9 与 e+U+0301）共享同一个缓存条目，发给模型的也是同一种写法
+func WithNormalizeUnicode(enabled bool) Option {
+	return func(o *options) {
+		o.normalizeUnicode = enabled
+	}
+}
+
+// normalizeInput 按配置把输入文本规范化为 NFC
+func (o *options) normalizeInput(text string) string {
+	if !o.normalizeUnicode {
+		return text
+	}
+	return norm.NFC.String(text)
+}
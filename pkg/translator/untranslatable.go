@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"log"
+	"regexp"
+	"strings"
+)
+
+// minCodeSignals 是一行被视为代码所需的最少特征数，位于代码块的花括号内也算一个特征
+const minCodeSignals = 2
+
+var (
+	// urlPattern 匹配整段只是一个网址的文本
+	urlPattern = regexp.MustCompile(`(?i)^(?:(?:https?|ftp)://|www\.)\S+$`)
+	// emailPattern 匹配整段只是一个邮箱地址的文本
+	emailPattern = regexp.MustCompile(`^[\w.+-]+@[\w-]+(?:\.[\w-]+)+$`)
+	// fencedCodePattern 匹配整段是 Markdown 代码块的文本
+	fencedCodePattern = regexp.MustCompile("(?s)^```.*```$")
+	// codeLinePatterns 是一行代码的特征：以 ; { } 结尾、含有比较或声明运算符、关键字后紧跟代码语法、
+	// 赋值语句、整行是函数调用、限定名的方法调用、标识符紧跟括号。单个特征在普通文本里也常见
+	// （如 "Total = 5 items"、"return to home page"），所以一行至少要有 minCodeSignals 个特征才算代码
+	codeLinePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`[;{}]$`),
+		regexp.MustCompile(`:=|=>|==|!=|&&|\|\||\+\+|<<|>>`),
+		regexp.MustCompile(`^(?:func|def|class|import|package|#include|const|let|var|return)\s+(?:[\w.]+\s*[(=:{<;]|["'(<]|[\w.*&]+$)`),
+		regexp.MustCompile(`^[\w.\[\]]+\s*[+\-*/:]?=\s*\S`),
+		regexp.MustCompile(`^[\w.]+\(.*\);?$`),
+		regexp.MustCompile(`\b[A-Za-z_]\w*\.[A-Za-z_]\w*\(`),
+		regexp.MustCompile(`[A-Za-z_]\w*\(`),
+	}
+)
+
+// WithForceTranslate 关闭不可翻译内容的检查：即使整段文本只是网址、邮箱或代码，也照常发给模型翻译
+func WithForceTranslate() Option {
+	return func(o *options) {
+		o.forceTranslate = true
+	}
+}
+
+// isUntranslatable 判断整段文本是否只是网址、邮箱地址或代码，这类内容翻译后反而出错
+func isUntranslatable(text string) bool {
+	s := strings.TrimSpace(text)
+	if s == "" {
+		return false
+	}
+	if urlPattern.MatchString(s) || emailPattern.MatchString(s) || fencedCodePattern.MatchString(s) {
+		return true
+	}
+	return isCode(s)
+}
+
+// isCode 判断文本是否是代码：除空行和注释外至少有一行，且每一行都像代码
+func isCode(s string) bool {
+	codeLines := 0
+	depth := 0
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if !isCodeLine(line, depth > 0) {
+			return false
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		codeLines++
+	}
+	return codeLines > 0
+}
+
+// isCodeLine 判断一行是否有足够的代码特征；inBlock 表示该行位于前面代码行打开的花括号内
+func isCodeLine(line string, inBlock bool) bool {
+	signals := 0
+	if inBlock {
+		signals++
+	}
+	for _, pattern := range codeLinePatterns {
+		if pattern.MatchString(line) {
+			signals++
+		}
+	}
+	return signals >= minCodeSignals
+}
+
+// skipUntranslatable 在未开启 WithForceTranslate 且文本不可翻译时返回 true，调用方应原样返回文本
+func (o *options) skipUntranslatable(text string) bool {
+	if o.forceTranslate || !isUntranslatable(text) {
+		return false
+	}
+	log.Printf("Skipping untranslatable content: %s", text)
+	return true
+}
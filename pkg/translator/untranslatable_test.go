@@ -0,0 +1,83 @@
+package translator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTranslate_Untranslatable(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		translate bool
+	}{
+		{"url", "https://example.com/docs?page=2", false},
+		{"www url", "www.example.com", false},
+		{"email", "support@example.co.uk", false},
+		{"code line", `fmt.Println("Hello, world")`, false},
+		{"code block", "if err != nil {\n\treturn err\n}", false},
+		{"assignment", "count := len(items)", false},
+		{"prose", "Hello, world", true},
+		{"prose with url", "Visit https://example.com for details", true},
+		{"prose with parentheses", "Call me later (maybe tomorrow)", true},
+		{"prose starting with let", "let me know if you need help", true},
+		{"prose starting with return", "return to home page", true},
+		{"prose starting with import", "import your contacts", true},
+		{"menu path with arrow", "File -> Open", true},
+		{"prose with equals", "Total = 5 items", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultCache.Clear()
+			llm := &fakeLLM{respond: func(string) (string, error) { return "译文", nil }}
+
+			got, err := Translate(context.Background(), llm, tt.text, "English", "Chinese")
+			if err != nil {
+				t.Fatalf("Translate() error = %v", err)
+			}
+			if tt.translate {
+				if got != "译文" || llm.Calls() != 1 {
+					t.Errorf("Translate() = %q with %d LLM calls, want a translation", got, llm.Calls())
+				}
+				return
+			}
+			if got != tt.text || llm.Calls() != 0 {
+				t.Errorf("Translate() = %q with %d LLM calls, want the input unchanged", got, llm.Calls())
+			}
+			if cached, ok := defaultCache.Get(tt.text, "English", "Chinese"); !ok || cached != tt.text {
+				t.Errorf("cache = %q, %v, want identity entry", cached, ok)
+			}
+
+			// WithForceTranslate 照常翻译
+			defaultCache.Clear()
+			if got, err := Translate(context.Background(), llm, tt.text, "English", "Chinese", WithForceTranslate()); err != nil || got != "译文" {
+				t.Errorf("Translate() with WithForceTranslate = %q, %v, want translation", got, err)
+			}
+		})
+	}
+}
+
+func TestIsUntranslatable(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{`fmt.Println("Hello, world")`, true},
+		{"count := len(items)", true},
+		{"if err != nil {\n\treturn err\n}", true},
+		{"let x = 5;", true},
+		{"func main() {\n\tfmt.Println(x)\n}", true},
+		{"import os", false},
+		{"let me know if you need help", false},
+		{"return to home page", false},
+		{"import your contacts", false},
+		{"File -> Open", false},
+		{"Total = 5 items", false},
+		{"Click Save; then close", false},
+	}
+	for _, tt := range tests {
+		if got := isUntranslatable(tt.text); got != tt.want {
+			t.Errorf("isUntranslatable(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// verifyRetries 是回译校验失败后的重新翻译次数
+const verifyRetries = 1
+
+// VerifiedTranslation 是经过回译校验的翻译结果
+type VerifiedTranslation struct {
+	Text            string // 译文
+	BackTranslation string // 译文回译成源语言的结果
+	Verified        bool   // 回译与原文语义是否一致
+	Attempts        int    // 翻译尝试次数
+}
+
+// TranslateVerified 翻译文本后再回译成源语言，比较回译与原文的语义是否一致。
+// 校验失败时会重新翻译一次；最终仍未通过时返回结果并将 Verified 置为 false，且不缓存译文。
+func TranslateVerified(ctx context.Context, llm llms.Model, text string, inputLanguage string, outputLanguage string, opts ...Option) (*VerifiedTranslation, error) {
+	forward, err := Translate(ctx, llm, text, inputLanguage, outputLanguage, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	for attempt := 1; ; attempt++ {
+		back, err := translateOnce(ctx, llm, forward, outputLanguage, inputLanguage, o)
+		if err != nil {
+			return nil, fmt.Errorf("back-translation failed: %w", err)
+		}
+
+		verified, err := sameMeaning(ctx, llm, text, back, inputLanguage, o)
+		if err != nil {
+			return nil, err
+		}
+
+		if verified || attempt > verifyRetries || !SpendRetry(ctx) {
+			cacheText := o.cacheNormalization.apply(text)
+			if verified {
+				o.cacheSet(ctx, cacheText, inputLanguage, outputLanguage, forward)
+			} else {
+				o.translationCache().deleteKey(o.cacheKey(ctx, cacheText, inputLanguage, outputLanguage))
+			}
+			return &VerifiedTranslation{
+				Text:            forward,
+				BackTranslation: back,
+				Verified:        verified,
+				Attempts:        attempt,
+			}, nil
+		}
+
+		log.Printf("Back-translation mismatch for '%s': got '%s', retrying", text, back)
+		forward, err = translateOnce(ctx, llm, text, inputLanguage, outputLanguage, o)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sameMeaning 判断原文与回译是否表达同一含义：先做规范化字符串比较，不一致时再由模型判断
+func sameMeaning(ctx context.Context, llm llms.Model, original, back string, language string, o *options) (bool, error) {
+	if normalizeForCompare(original) == normalizeForCompare(back) {
+		return true, nil
+	}
+
+	reply, err := runPrompt(ctx, llm, o,
+		`Do the following two {{.language}} texts have the same meaning? Answer "yes" or "no" only.
+Text A: {{.original}}
+Text B: {{.back}}`,
+		map[string]any{
+			"language": language,
+			"original": original,
+			"back":     back,
+		})
+	if err != nil {
+		return false, fmt.Errorf("verification failed: %w", err)
+	}
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(reply)), "yes"), nil
+}
+
+// normalizeForCompare 忽略大小写、标点和多余空白，用于粗略比较两段文本
+func normalizeForCompare(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsPunct(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,80 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateVerified_GoodRoundTrip(t *testing.T) {
+	defaultCache.Clear()
+	llm := newDictLLM(map[string]string{
+		`"Hello world" from English to Chinese`: "你好，世界",
+		`"你好，世界" from Chinese to English`:       "hello world!",
+	})
+
+	result, err := TranslateVerified(context.Background(), llm, "Hello world", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateVerified() error = %v", err)
+	}
+	if !result.Verified {
+		t.Errorf("expected verified round trip, got back-translation %q", result.BackTranslation)
+	}
+	if result.Text != "你好，世界" || result.Attempts != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	// 规范化比较一致时不需要模型判断
+	if llm.Calls() != 2 {
+		t.Errorf("expected 2 LLM calls, got %d", llm.Calls())
+	}
+}
+
+func TestTranslateVerified_BadRoundTrip(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		switch {
+		case strings.HasPrefix(prompt, "Do the following"):
+			return "no", nil
+		case strings.Contains(prompt, "from English to Chinese"):
+			return "再见", nil
+		default:
+			return "Goodbye", nil
+		}
+	}}
+
+	result, err := TranslateVerified(context.Background(), llm, "Hello world", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateVerified() error = %v", err)
+	}
+	if result.Verified {
+		t.Error("expected verification to fail")
+	}
+	if result.Attempts != 1+verifyRetries {
+		t.Errorf("Attempts = %d, want %d", result.Attempts, 1+verifyRetries)
+	}
+	if _, ok := defaultCache.Get("Hello world", "English", "Chinese"); ok {
+		t.Error("unverified translation should not remain cached")
+	}
+}
+
+func TestTranslateVerified_JudgedSameMeaning(t *testing.T) {
+	defaultCache.Clear()
+	llm := &fakeLLM{respond: func(prompt string) (string, error) {
+		switch {
+		case strings.HasPrefix(prompt, "Do the following"):
+			return "Yes.", nil
+		case strings.Contains(prompt, "from English to Chinese"):
+			return "你好，世界", nil
+		default:
+			return "Hi, world", nil
+		}
+	}}
+
+	result, err := TranslateVerified(context.Background(), llm, "Hello world", "English", "Chinese")
+	if err != nil {
+		t.Fatalf("TranslateVerified() error = %v", err)
+	}
+	if !result.Verified {
+		t.Error("expected model judgment to verify the round trip")
+	}
+}